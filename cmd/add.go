@@ -1,14 +1,11 @@
 package cmd
 
 import (
-	"fmt"
 	"io"
-	"log"
 	"os"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
-	"github.com/robyparr/wh/model"
 	"github.com/robyparr/wh/repository"
 	"github.com/robyparr/wh/util"
 	"github.com/spf13/cobra"
@@ -18,9 +15,9 @@ var addCmd = &cobra.Command{
 	Use:   "add [date]",
 	Short: "Adds a new work day",
 	Run: func(cmd *cobra.Command, args []string) {
-		repo, err := repository.NewRepo(repository.DefaultDatabasePath)
+		repo, err := openRepo()
 		if err != nil {
-			log.Fatalln(err)
+			fatal(err)
 		}
 
 		var dateStr string
@@ -32,7 +29,7 @@ var addCmd = &cobra.Command{
 		noteStr := mustGetStringFlag(cmd, "note")
 
 		if err := runAddCmd(os.Stdout, repo, dateStr, lengthStr, noteStr); err != nil {
-			log.Fatalln(err)
+			fatal(err)
 		}
 	},
 }
@@ -46,7 +43,7 @@ func init() {
 func runAddCmd(w io.Writer, repo *repository.Repo, dateStr string, lengthStr string, note string) error {
 	date := util.TodayAtMidnight()
 	if dateStr != "" {
-		parsedDate, err := time.ParseInLocation(util.DateFormatStr, dateStr, time.Local)
+		parsedDate, err := util.ParseDateString(dateStr)
 		if err != nil {
 			return err
 		}
@@ -60,11 +57,14 @@ func runAddCmd(w io.Writer, repo *repository.Repo, dateStr string, lengthStr str
 	}
 
 	if workDay.Id != 0 {
-		fmt.Fprintf(w, "Work day on %s already exists.\n", util.FormatDate(date))
-		return nil
+		return printMessage(w, "Work day on %s already exists.\n", util.FormatDate(date))
+	}
+
+	workDay, err = newWorkDay(repo, date)
+	if err != nil {
+		return err
 	}
 
-	workDay = model.NewWorkDay(date)
 	if lengthStr != "" {
 		dur, err := time.ParseDuration(lengthStr)
 		if err != nil {
@@ -83,6 +83,5 @@ func runAddCmd(w io.Writer, repo *repository.Repo, dateStr string, lengthStr str
 		return err
 	}
 
-	fmt.Fprintf(w, "Added work day #%d on %s\n", workDay.Id, util.FormatDate(workDay.Date))
-	return nil
+	return printResult(w, newWorkDayResult(workDay), "Added work day #%d on %s\n", workDay.Id, util.FormatDate(workDay.Date))
 }
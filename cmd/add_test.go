@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/robyparr/wh/config"
 	"github.com/robyparr/wh/model"
 	"github.com/robyparr/wh/util"
 	"github.com/robyparr/wh/util/testutil"
@@ -22,7 +23,7 @@ func TestRunAddCmd(t *testing.T) {
 		expectedWorkDay model.WorkDay
 	}
 
-	date := time.Date(2023, 8, 13, 0, 0, 0, 0, time.Local)
+	date := time.Date(2023, 8, 14, 0, 0, 0, 0, time.Local)
 	var defaultLength int = 7.5 * 60
 
 	testCases := []testCase{
@@ -32,15 +33,15 @@ func TestRunAddCmd(t *testing.T) {
 			expectedWorkDay: model.WorkDay{
 				Id:         1,
 				Date:       util.TodayAtMidnight(),
-				LengthMins: defaultLength,
+				LengthMins: defaultLengthOn(util.TodayAtMidnight()),
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
 			},
 		},
 		{
 			title:          "add with date arg",
-			dateStr:        "2023-08-13",
-			expectedOutput: "Added work day #1 on 2023-08-13\n",
+			dateStr:        "2023-08-14",
+			expectedOutput: "Added work day #1 on 2023-08-14\n",
 			expectedWorkDay: model.WorkDay{
 				Id:         1,
 				Date:       date,
@@ -51,9 +52,9 @@ func TestRunAddCmd(t *testing.T) {
 		},
 		{
 			title:          "add with length arg",
-			dateStr:        "2023-08-13",
+			dateStr:        "2023-08-14",
 			lengthStr:      "1h30m",
-			expectedOutput: "Added work day #1 on 2023-08-13\n",
+			expectedOutput: "Added work day #1 on 2023-08-14\n",
 			expectedWorkDay: model.WorkDay{
 				Id:         1,
 				Date:       date,
@@ -64,9 +65,9 @@ func TestRunAddCmd(t *testing.T) {
 		},
 		{
 			title:          "add with note arg",
-			dateStr:        "2023-08-13",
+			dateStr:        "2023-08-14",
 			note:           "This is a note.",
-			expectedOutput: "Added work day #1 on 2023-08-13\n",
+			expectedOutput: "Added work day #1 on 2023-08-14\n",
 			expectedWorkDay: model.WorkDay{
 				Id:         1,
 				Date:       date,
@@ -116,3 +117,102 @@ func TestRunAddCmdExistingDay(t *testing.T) {
 		t.Errorf("Unexpected work day count; got %d, want %d\n", gotCount, wantCount)
 	}
 }
+
+func TestRunAddCmdConfiguredDayLength(t *testing.T) {
+	prevCfg := cfg
+	cfg.DayLength = config.Duration(8 * time.Hour)
+	t.Cleanup(func() { cfg = prevCfg })
+
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runAddCmd(out, repo, "2023-08-14", "", "")
+	testutil.AssertNoErr(t, err)
+
+	got, err := repo.GetWorkDayByDate(time.Date(2023, 8, 14, 0, 0, 0, 0, time.Local))
+	testutil.AssertNoErr(t, err)
+
+	if got.LengthMins != 8*60 {
+		t.Errorf("got length %d, want %d", got.LengthMins, 8*60)
+	}
+}
+
+func TestRunAddCmdWeekendWithoutSchedule(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	testCases := []struct {
+		dateStr    string
+		lengthStr  string
+		wantLength int
+	}{
+		{dateStr: "2023-09-08", wantLength: model.DefaultDayLengthMins},
+		{dateStr: "2023-09-09", wantLength: 0},
+		{dateStr: "2023-09-10", wantLength: 0},
+		{dateStr: "2023-09-16", lengthStr: "2h", wantLength: 2 * 60},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.dateStr, func(t *testing.T) {
+			err := runAddCmd(&bytes.Buffer{}, repo, tc.dateStr, tc.lengthStr, "")
+			testutil.AssertNoErr(t, err)
+
+			date, err := util.ParseDateString(tc.dateStr)
+			testutil.AssertNoErr(t, err)
+
+			got, err := repo.GetWorkDayByDate(date)
+			testutil.AssertNoErr(t, err)
+
+			if got.LengthMins != tc.wantLength {
+				t.Errorf("got length %d, want %d", got.LengthMins, tc.wantLength)
+			}
+		})
+	}
+}
+
+func TestRunAddCmdWithSchedule(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	schedule := model.NewSchedule(time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local), 8*time.Hour)
+	schedule.SetLength(time.Friday, 4*time.Hour)
+	_, err := repo.CreateSchedule(schedule)
+	testutil.AssertNoErr(t, err)
+
+	testCases := []struct {
+		dateStr    string
+		lengthStr  string
+		wantLength int
+	}{
+		{dateStr: "2023-09-07", wantLength: 8 * 60},
+		{dateStr: "2023-09-08", wantLength: 4 * 60},
+		{dateStr: "2023-09-09", wantLength: 0},
+		{dateStr: "2023-09-10", lengthStr: "2h", wantLength: 2 * 60},
+		{dateStr: "2022-12-30", wantLength: model.DefaultDayLengthMins},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.dateStr, func(t *testing.T) {
+			err := runAddCmd(&bytes.Buffer{}, repo, tc.dateStr, tc.lengthStr, "")
+			testutil.AssertNoErr(t, err)
+
+			date, err := util.ParseDateString(tc.dateStr)
+			testutil.AssertNoErr(t, err)
+
+			got, err := repo.GetWorkDayByDate(date)
+			testutil.AssertNoErr(t, err)
+
+			if got.LengthMins != tc.wantLength {
+				t.Errorf("got length %d, want %d", got.LengthMins, tc.wantLength)
+			}
+		})
+	}
+}
+
+// defaultLengthOn is the length new work days get on the date without a
+// schedule, for tests that depend on today's date.
+func defaultLengthOn(date time.Time) int {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return 0
+	}
+
+	return model.DefaultDayLengthMins
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+type balanceAdjustCmdArgs struct {
+	amountStr string
+	dateStr   string
+	reason    string
+}
+
+var balanceCmd = &cobra.Command{
+	Use:   "balance",
+	Short: "Shows the flextime balance across all work days",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		sinceStr := mustGetStringFlag(cmd, "since")
+		if err := runBalanceCmd(os.Stdout, repo, sinceStr); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var balanceAdjustCmd = &cobra.Command{
+	Use:   "adjust <amount>",
+	Short: "Adjusts the flextime balance (e.g. carry over 3h or pay out -8h)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := balanceAdjustCmdArgs{
+			amountStr: args[0],
+			dateStr:   mustGetStringFlag(cmd, "date"),
+			reason:    mustGetStringFlag(cmd, "reason"),
+		}
+
+		if err := runBalanceAdjustCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	balanceCmd.Flags().StringP("since", "s", "", "only count work days from this date on (default balance_start from the config file)")
+
+	balanceAdjustCmd.Flags().StringP("reason", "r", "", "reason for the adjustment (required)")
+	balanceAdjustCmd.Flags().StringP("date", "d", "", "date of the adjustment (default today)")
+	balanceAdjustCmd.MarkFlagRequired("reason")
+
+	balanceCmd.AddCommand(balanceAdjustCmd)
+	rootCmd.AddCommand(balanceCmd)
+}
+
+// runBalanceCmd adds up the balance of every work day before today, since the
+// day in progress would otherwise always count against the balance.
+func runBalanceCmd(out io.Writer, repo *repository.Repo, sinceStr string) error {
+	if sinceStr == "" {
+		sinceStr = cfg.BalanceStart
+	}
+
+	var since time.Time
+	if sinceStr != "" {
+		date, err := util.ParseDateString(sinceStr)
+		if err != nil {
+			return fmt.Errorf("error parsing since date: %v", err)
+		}
+
+		since = date
+	}
+
+	today := util.TodayAtMidnight()
+	through := today.AddDate(0, 0, -1)
+	workDays, err := repo.GetWorkDaysInRange(since, through)
+	if err != nil {
+		return fmt.Errorf("error loading work days: %v", err)
+	}
+
+	adjustments, err := repo.GetBalanceAdjustmentsInRange(since, today)
+	if err != nil {
+		return fmt.Errorf("error loading balance adjustments: %v", err)
+	}
+
+	var expected, worked, adjusted time.Duration
+	for _, wd := range workDays {
+		expected += wd.Length()
+		worked += wd.TimeWorked()
+	}
+
+	vm := balanceViewModel{
+		Title:   "Flextime Balance",
+		Since:   "-",
+		Through: util.FormatDate(through),
+		Days:    len(workDays),
+	}
+	if !since.IsZero() {
+		vm.Since = util.FormatDate(since)
+	}
+
+	result := balanceResult{
+		Through:            through.Format(util.DateFormatStr),
+		Days:               len(workDays),
+		ExpectedMins:       int(expected.Minutes()),
+		WorkedMins:         int(worked.Minutes()),
+		BalanceAdjustments: []balanceAdjustmentResult{},
+	}
+	if !since.IsZero() {
+		result.Since = since.Format(util.DateFormatStr)
+	}
+
+	for _, a := range adjustments {
+		adjusted += a.Amount()
+		vm.BalanceAdjustments = append(vm.BalanceAdjustments, newBalanceAdjustmentViewModel(a))
+		result.BalanceAdjustments = append(result.BalanceAdjustments, newBalanceAdjustmentResult(a))
+	}
+
+	vm.Expected = util.FormatDuration(expected)
+	vm.Worked = util.FormatDuration(worked)
+	vm.Adjustments = util.FormatBalance(adjusted)
+	vm.Balance = util.FormatBalance(worked - expected + adjusted)
+
+	result.AdjustmentsMins = int(adjusted.Minutes())
+	result.BalanceMins = int((worked - expected + adjusted).Minutes())
+
+	return renderResult(out, "balance_show.txt", vm, result)
+}
+
+func runBalanceAdjustCmd(out io.Writer, repo *repository.Repo, args balanceAdjustCmdArgs) error {
+	if args.reason == "" {
+		return errors.New("a reason is required for balance adjustments")
+	}
+
+	amount, err := time.ParseDuration(args.amountStr)
+	if err != nil {
+		return fmt.Errorf("error parsing amount: %v", err)
+	}
+
+	date := util.TodayAtMidnight()
+	if args.dateStr != "" {
+		date, err = util.ParseDateString(args.dateStr)
+		if err != nil {
+			return fmt.Errorf("error parsing date: %v", err)
+		}
+	}
+
+	adjustment, err := repo.CreateBalanceAdjustment(model.NewBalanceAdjustment(date, amount, args.reason))
+	if err != nil {
+		return fmt.Errorf("error creating balance adjustment: %v", err)
+	}
+
+	return printResult(out, newBalanceAdjustmentResult(adjustment), "Added balance adjustment #%d of %s on %s.\n", adjustment.Id, util.FormatBalance(adjustment.Amount()), util.FormatDate(adjustment.Date))
+}
+
+type balanceViewModel struct {
+	Title              string
+	Since              string
+	Through            string
+	Days               int
+	Expected           string
+	Worked             string
+	Adjustments        string
+	Balance            string
+	BalanceAdjustments []balanceAdjustmentViewModel
+}
+
+type balanceAdjustmentViewModel struct {
+	Id     int
+	Date   string
+	Amount string
+	Reason string
+}
+
+func newBalanceAdjustmentViewModel(a model.BalanceAdjustment) balanceAdjustmentViewModel {
+	return balanceAdjustmentViewModel{
+		Id:     a.Id,
+		Date:   util.FormatDate(a.Date),
+		Amount: util.FormatBalance(a.Amount()),
+		Reason: a.Reason,
+	}
+}
+
+// balanceResult is the flextime balance in the json and tsv output formats,
+// with the balance adjustments as the tsv rows. Since is empty when every
+// work day counts.
+type balanceResult struct {
+	Since              string                    `json:"since"`
+	Through            string                    `json:"through"`
+	Days               int                       `json:"days"`
+	ExpectedMins       int                       `json:"expected_mins"`
+	WorkedMins         int                       `json:"worked_mins"`
+	AdjustmentsMins    int                       `json:"adjustments_mins"`
+	BalanceMins        int                       `json:"balance_mins"`
+	BalanceAdjustments []balanceAdjustmentResult `json:"balance_adjustments" tsv:"rows"`
+}
+
+type balanceAdjustmentResult struct {
+	Id         int    `json:"id"`
+	Date       string `json:"date"`
+	AmountMins int    `json:"amount_mins"`
+	Reason     string `json:"reason"`
+}
+
+func newBalanceAdjustmentResult(a model.BalanceAdjustment) balanceAdjustmentResult {
+	return balanceAdjustmentResult{
+		Id:         a.Id,
+		Date:       a.Date.Format(util.DateFormatStr),
+		AmountMins: int(a.Amount().Minutes()),
+		Reason:     a.Reason,
+	}
+}
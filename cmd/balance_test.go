@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunBalanceCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	today := util.TodayAtMidnight()
+
+	worked := map[time.Time]time.Duration{
+		time.Date(2023, 8, 31, 0, 0, 0, 0, time.Local): 8 * time.Hour,
+		time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local):  9 * time.Hour,
+		today: 1 * time.Hour,
+	}
+	for date, duration := range worked {
+		wd, err := repo.CreateWorkDay(model.NewWorkDay(date))
+		testutil.AssertNoErr(t, err)
+
+		wp := model.NewWorkPeriod(wd)
+		wp.StartAt = date
+		wp.SetEndAt(wp.StartAt.Add(duration))
+		_, err = repo.CreateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+	}
+
+	_, err := repo.CreateBalanceAdjustment(model.NewBalanceAdjustment(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local), -1*time.Hour, "Paid out."))
+	testutil.AssertNoErr(t, err)
+
+	t.Run("all days", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runBalanceCmd(out, repo, "")
+		testutil.AssertNoErr(t, err)
+
+		compareShowOutput(
+			t,
+			out.String(),
+			`
+Flextime Balance
+================
+
+Since:		-
+Through:	:through
+Days:		2
+Expected:	15h0m
+Worked:		17h0m
+Adjustments:	-1h0m
+Balance:	+1h0m
+
+ADJUSTMENTS
+ID	DATE		AMOUNT	REASON
+1	2023-09-01	-1h0m	Paid out.
+`,
+			map[string]string{"through": util.FormatDate(today.AddDate(0, 0, -1))},
+		)
+	})
+
+	t.Run("since date", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runBalanceCmd(out, repo, "2023-09-01")
+		testutil.AssertNoErr(t, err)
+
+		compareShowOutput(
+			t,
+			out.String(),
+			`
+Flextime Balance
+================
+
+Since:		2023-09-01
+Through:	:through
+Days:		1
+Expected:	7h30m
+Worked:		9h0m
+Adjustments:	-1h0m
+Balance:	+30m
+
+ADJUSTMENTS
+ID	DATE		AMOUNT	REASON
+1	2023-09-01	-1h0m	Paid out.
+`,
+			map[string]string{"through": util.FormatDate(today.AddDate(0, 0, -1))},
+		)
+	})
+}
+
+func TestRunBalanceAdjustCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	out := &bytes.Buffer{}
+
+	err := runBalanceAdjustCmd(out, repo, balanceAdjustCmdArgs{amountStr: "3h30m", dateStr: "2023-09-01", reason: "Carried over."})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Added balance adjustment #1 of +3h30m on 2023-09-01.\n")
+
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	adjustments, err := repo.GetBalanceAdjustmentsInRange(date, date)
+	testutil.AssertNoErr(t, err)
+
+	if len(adjustments) != 1 {
+		t.Fatalf("Expected 1 balance adjustment, got %d", len(adjustments))
+	}
+
+	testutil.AssertEqualStructs(t, adjustments[0], model.BalanceAdjustment{
+		Id:         1,
+		Date:       date,
+		AmountMins: 210,
+		Reason:     "Carried over.",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+
+	t.Run("without reason", func(t *testing.T) {
+		err := runBalanceAdjustCmd(&bytes.Buffer{}, repo, balanceAdjustCmdArgs{amountStr: "1h"})
+		if err == nil {
+			t.Error("Expected an error but got none")
+		}
+	})
+}
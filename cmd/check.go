@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/robyparr/wh/compliance"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Checks work days against the working time rules",
+	Long: `Checks work days against the working time rules: the maximum time worked
+per day, the break needed after working a while and the rest needed between
+work days. The rules are set in the [compliance] section of the config file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := runCheckCmd(os.Stdout, repo, mustGetDateRangeFlags(cmd)); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	addDateRangeFlags(checkCmd)
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheckCmd(out io.Writer, repo *repository.Repo, args dateRangeArgs) error {
+	from, to, err := args.resolve()
+	if err != nil {
+		return err
+	}
+
+	violations, err := checkCompliance(repo, from, to)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) == 0 {
+		return printResult(out, newCheckResult(from, to, violations), "No violations between %s and %s.\n", util.FormatDate(from), util.FormatDate(to))
+	}
+
+	vm := checkViewModel{
+		Title: fmt.Sprintf("%s to %s", util.FormatDate(from), util.FormatDate(to)),
+	}
+	for _, v := range violations {
+		vm.Violations = append(vm.Violations, checkViolationViewModel{
+			Date:    util.FormatDate(v.Date) + v.Date.Format(" Mon"),
+			Rule:    string(v.Rule),
+			Message: v.Message,
+		})
+	}
+
+	return renderResult(out, "compliance_check.txt", vm, newCheckResult(from, to, violations))
+}
+
+// checkCompliance checks the work days between from and to against the
+// configured rules. The day before from is loaded too, for the rest rule.
+func checkCompliance(repo *repository.Repo, from time.Time, to time.Time) ([]compliance.Violation, error) {
+	workDays, err := repo.GetWorkDaysInRange(from.AddDate(0, 0, -1), to)
+	if err != nil {
+		return nil, fmt.Errorf("error loading work days: %v", err)
+	}
+
+	var violations []compliance.Violation
+	for _, v := range cfg.ComplianceRules().Check(workDays) {
+		if !v.Date.Before(from) {
+			violations = append(violations, v)
+		}
+	}
+
+	return violations, nil
+}
+
+type checkViewModel struct {
+	Title      string
+	Violations []checkViolationViewModel
+}
+
+type checkViolationViewModel struct {
+	Date    string
+	Rule    string
+	Message string
+}
+
+// checkResult is the violations found by check in the json and tsv output
+// formats, with the violations as the tsv rows.
+type checkResult struct {
+	From       string                 `json:"from"`
+	To         string                 `json:"to"`
+	Violations []checkViolationResult `json:"violations" tsv:"rows"`
+}
+
+type checkViolationResult struct {
+	Date    string `json:"date"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func newCheckResult(from time.Time, to time.Time, violations []compliance.Violation) checkResult {
+	result := checkResult{
+		From:       from.Format(util.DateFormatStr),
+		To:         to.Format(util.DateFormatStr),
+		Violations: []checkViolationResult{},
+	}
+
+	for _, v := range violations {
+		result.Violations = append(result.Violations, checkViolationResult{
+			Date:    v.Date.Format(util.DateFormatStr),
+			Rule:    string(v.Rule),
+			Message: v.Message,
+		})
+	}
+
+	return result
+}
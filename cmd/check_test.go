@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunCheckCmdNoViolations(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runCheckCmd(out, repo, dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-30"})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "No violations between 2023-09-01 and 2023-09-30.\n")
+}
+
+func TestRunCheckCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	logPeriod := func(day int, startHour int, endHour int) {
+		t.Helper()
+
+		date := time.Date(2023, 9, day, 0, 0, 0, 0, time.Local)
+		wd, err := repo.GetWorkDayByDate(date)
+		testutil.AssertNoErr(t, err)
+		if wd.Id == 0 {
+			wd, err = repo.CreateWorkDay(model.NewWorkDay(date))
+			testutil.AssertNoErr(t, err)
+		}
+
+		wp := model.NewWorkPeriod(wd)
+		wp.StartAt = date.Add(time.Duration(startHour) * time.Hour)
+		wp.SetEndAt(date.Add(time.Duration(endHour) * time.Hour))
+		_, err = repo.CreateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+	}
+
+	// The day before the range only counts towards the rest on the first day.
+	logPeriod(1, 12, 23)
+	logPeriod(2, 6, 12)
+	logPeriod(2, 13, 16)
+	logPeriod(4, 7, 18)
+
+	out := &bytes.Buffer{}
+	err := runCheckCmd(out, repo, dateRangeArgs{fromStr: "2023-09-02", toStr: "2023-09-30"})
+	testutil.AssertNoErr(t, err)
+
+	compareShowOutput(
+		t,
+		out.String(),
+		`
+2023-09-02 to 2023-09-30
+========================
+
+DATE		RULE		MESSAGE
+2023-09-02 Sat	rest     	rested 7h0m since the previous work day, less than the minimum of 11h0m
+2023-09-04 Mon	max-daily	worked 11h0m, more than the maximum of 10h0m
+2023-09-04 Mon	break    	worked 11h0m with 0m of breaks, at least 30m needed after 6h0m
+`,
+		nil,
+	)
+}
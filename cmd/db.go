@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manages the database",
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Applies pending database migrations",
+	Long: `Applies pending database migrations.
+
+Migrations are also applied automatically whenever the database is opened.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openUnmigratedRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		status := mustGetBoolFlag(cmd, "status")
+		if err := runDbMigrateCmd(os.Stdout, repo, status); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var dbInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Creates the database if it doesn't exist yet",
+	Long: `Creates the database if it doesn't exist yet.
+
+Databases are also created whenever they're first opened, except at the
+default location while the working directory has a database from an older
+version of wh. Run this to start a new, empty database there anyway.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, _, err := resolveDatabasePath()
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := runDbInitCmd(os.Stdout, path); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	dbMigrateCmd.Flags().BoolP("status", "s", false, "show which migrations have been applied instead")
+
+	dbCmd.AddCommand(dbInitCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func runDbInitCmd(out io.Writer, path string) error {
+	existed := fileExists(path)
+	if err := createDatabaseDir(path); err != nil {
+		return err
+	}
+
+	if _, err := repository.NewRepo(path); err != nil {
+		return err
+	}
+
+	result := dbInitResult{Path: path, Created: !existed}
+	if existed {
+		return printResult(out, result, "Database at %s already exists.\n", path)
+	}
+
+	return printResult(out, result, "Created database at %s.\n", path)
+}
+
+// dbInitResult is the database path and whether db init created it.
+type dbInitResult struct {
+	Path    string `json:"path"`
+	Created bool   `json:"created"`
+}
+
+func runDbMigrateCmd(out io.Writer, repo *repository.Repo, status bool) error {
+	if status {
+		return runDbMigrateStatus(out, repo)
+	}
+
+	applied, err := repo.Migrate()
+	if err != nil {
+		return err
+	}
+
+	result := migrationListResult{Migrations: []migrationResult{}}
+	var text strings.Builder
+	for _, m := range applied {
+		result.Migrations = append(result.Migrations, newMigrationResult(m))
+		fmt.Fprintf(&text, "Applied migration %d (%s).\n", m.Version, m.Name)
+	}
+
+	if len(applied) == 0 {
+		text.WriteString("No pending migrations.\n")
+	}
+
+	return printResult(out, result, "%s", text.String())
+}
+
+func runDbMigrateStatus(out io.Writer, repo *repository.Repo) error {
+	migrations, err := repo.GetMigrations()
+	if err != nil {
+		return fmt.Errorf("error loading migrations: %v", err)
+	}
+
+	var vm migrationStatusViewModel
+	result := migrationListResult{Migrations: []migrationResult{}}
+	for _, m := range migrations {
+		vm.Migrations = append(vm.Migrations, newMigrationViewModel(m))
+		result.Migrations = append(result.Migrations, newMigrationResult(m))
+	}
+
+	return renderResult(out, "migration_status.txt", vm, result)
+}
+
+type migrationStatusViewModel struct {
+	Migrations []migrationViewModel
+}
+
+type migrationViewModel struct {
+	Version   int
+	Name      string
+	AppliedAt string
+}
+
+func newMigrationViewModel(m repository.Migration) migrationViewModel {
+	appliedAt := "pending"
+	if m.AppliedAt.Valid {
+		appliedAt = util.FormatDateTime(m.AppliedAt.Time)
+	}
+
+	return migrationViewModel{Version: m.Version, Name: m.Name, AppliedAt: appliedAt}
+}
+
+// migrationListResult is the migrations in the json and tsv output formats,
+// with the migrations as the tsv rows. Pending migrations have an empty
+// applied at.
+type migrationListResult struct {
+	Migrations []migrationResult `json:"migrations" tsv:"rows"`
+}
+
+type migrationResult struct {
+	Version   int    `json:"version"`
+	Name      string `json:"name"`
+	AppliedAt string `json:"applied_at"`
+}
+
+func newMigrationResult(m repository.Migration) migrationResult {
+	result := migrationResult{Version: m.Version, Name: m.Name}
+	if m.AppliedAt.Valid {
+		result.AppliedAt = m.AppliedAt.Time.Format(time.RFC3339)
+	}
+
+	return result
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunDbInitCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wh", "wh.sqlite")
+
+	t.Run("new database", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runDbInitCmd(out, path))
+		testutil.AssertOutput(t, out, fmt.Sprintf("Created database at %s.\n", path))
+
+		if !repository.IsDatabase(path) {
+			t.Error("Expected a migrated database to be created")
+		}
+	})
+
+	t.Run("existing database", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runDbInitCmd(out, path))
+		testutil.AssertOutput(t, out, fmt.Sprintf("Database at %s already exists.\n", path))
+	})
+}
+
+func TestRunDbMigrateCmd(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runDbMigrateCmd(out, repo, false)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "No pending migrations.\n")
+}
+
+func TestRunDbMigrateCmdStatus(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	migrations, err := repo.GetMigrations()
+	testutil.AssertNoErr(t, err)
+
+	err = runDbMigrateCmd(out, repo, true)
+	testutil.AssertNoErr(t, err)
+
+	want := "VERSION\tNAME                    \tAPPLIED AT\n"
+	for _, m := range migrations {
+		want += fmt.Sprintf("%d\t%-24s\t%s\n", m.Version, m.Name, util.FormatDateTime(m.AppliedAt.Time))
+	}
+
+	testutil.AssertOutput(t, out, want)
+}
+
+func TestRunDbMigrateCmdUnmigrated(t *testing.T) {
+	repo, err := repository.Open(":memory:")
+	testutil.AssertNoErr(t, err)
+
+	migrations, err := repo.GetMigrations()
+	testutil.AssertNoErr(t, err)
+
+	t.Run("status", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runDbMigrateCmd(out, repo, true)
+		testutil.AssertNoErr(t, err)
+
+		want := "VERSION\tNAME                    \tAPPLIED AT\n"
+		for _, m := range migrations {
+			want += fmt.Sprintf("%d\t%-24s\tpending\n", m.Version, m.Name)
+		}
+
+		testutil.AssertOutput(t, out, want)
+	})
+
+	t.Run("migrate", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runDbMigrateCmd(out, repo, false)
+		testutil.AssertNoErr(t, err)
+
+		var want string
+		for _, m := range migrations {
+			want += fmt.Sprintf("Applied migration %d (%s).\n", m.Version, m.Name)
+		}
+
+		testutil.AssertOutput(t, out, want)
+	})
+
+	t.Run("migrate again", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runDbMigrateCmd(out, repo, false)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "No pending migrations.\n")
+	})
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+type editPeriodCmdArgs struct {
+	id       int
+	startStr string
+	endStr   string
+	note     string
+}
+
+type editDayCmdArgs struct {
+	dateStr   string
+	lengthStr string
+	note      string
+}
+
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edits an existing work period or work day",
+}
+
+var editPeriodCmd = &cobra.Command{
+	Use:   "period <id>",
+	Short: "Edits a work period",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			fatal(fmt.Errorf("invalid work period ID '%s'", args[0]))
+		}
+
+		cmdArgs := editPeriodCmdArgs{
+			id:       id,
+			startStr: mustGetStringFlag(cmd, "start"),
+			endStr:   mustGetStringFlag(cmd, "end"),
+			note:     mustGetStringFlag(cmd, "note"),
+		}
+
+		if err := runEditPeriodCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var editDayCmd = &cobra.Command{
+	Use:   "day <date>",
+	Short: "Edits a work day",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := editDayCmdArgs{
+			dateStr:   args[0],
+			lengthStr: mustGetStringFlag(cmd, "length"),
+			note:      mustGetStringFlag(cmd, "note"),
+		}
+
+		if err := runEditDayCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	editPeriodCmd.Flags().StringP("start", "s", "", "new start time (e.g. 09:05)")
+	editPeriodCmd.Flags().StringP("end", "e", "", "new end time (e.g. 12:00)")
+	editPeriodCmd.Flags().StringP("note", "n", "", "new work period note")
+
+	editDayCmd.Flags().StringP("length", "l", "", "new work day length (e.g. 6h)")
+	editDayCmd.Flags().StringP("note", "n", "", "new work day note")
+
+	editCmd.AddCommand(editPeriodCmd)
+	editCmd.AddCommand(editDayCmd)
+	rootCmd.AddCommand(editCmd)
+}
+
+func runEditPeriodCmd(out io.Writer, repo *repository.Repo, args editPeriodCmdArgs) error {
+	period, err := repo.GetWorkPeriodById(args.id)
+	if err != nil {
+		return fmt.Errorf("error loading work period: %v", err)
+	}
+
+	if period.Id == 0 {
+		return printMessage(out, "Unable to find work period #%d.\n", args.id)
+	}
+
+	workDay, err := repo.GetWorkDayById(period.WorkDayId)
+	if err != nil {
+		return fmt.Errorf("error loading work day: %v", err)
+	}
+
+	if args.startStr != "" {
+		period.StartAt, err = util.ParseTimeStringOn(args.startStr, workDay.Date)
+		if err != nil {
+			return fmt.Errorf("error parsing start time: %v", err)
+		}
+	}
+
+	if args.endStr != "" {
+		endAt, err := util.ParseTimeStringOn(args.endStr, workDay.Date)
+		if err != nil {
+			return fmt.Errorf("error parsing end time: %v", err)
+		}
+
+		period.SetEndAt(endAt)
+	}
+
+	if args.note != "" {
+		period.SetNote(args.note)
+		period.AddTags(model.ParseNoteTags(args.note)...)
+	}
+
+	if err := period.Validate(); err != nil {
+		return err
+	}
+
+	periods, err := repo.GetWorkPeriods(workDay)
+	if err != nil {
+		return fmt.Errorf("error loading work periods: %v", err)
+	}
+
+	for _, other := range periods {
+		if other.Id != period.Id && period.Overlaps(other) {
+			return fmt.Errorf("work period would overlap with work period #%d", other.Id)
+		}
+	}
+
+	period, err = repo.UpdateWorkPeriod(period)
+	if err != nil {
+		return fmt.Errorf("error updating work period: %v", err)
+	}
+
+	return printResult(out, newWorkPeriodResult(period), "Updated work period #%d.\n", period.Id)
+}
+
+func runEditDayCmd(out io.Writer, repo *repository.Repo, args editDayCmdArgs) error {
+	date, err := util.ParseDateString(args.dateStr)
+	if err != nil {
+		return fmt.Errorf("error parsing date: %v", err)
+	}
+
+	workDay, err := repo.GetWorkDayByDate(date)
+	if err != nil {
+		return fmt.Errorf("error loading work day: %v", err)
+	}
+
+	if workDay.Id == 0 {
+		return printMessage(out, "No work day for %s yet.\n", util.FormatDate(date))
+	}
+
+	if args.lengthStr != "" {
+		duration, err := time.ParseDuration(args.lengthStr)
+		if err != nil {
+			return fmt.Errorf("error parsing length string: %v", err)
+		}
+
+		if duration < 0 {
+			return errors.New("work day length must not be negative")
+		}
+
+		workDay.LengthMins = int(duration.Minutes())
+	}
+
+	if args.note != "" {
+		workDay.SetNote(args.note)
+	}
+
+	if _, err := repo.UpdateWorkDay(workDay); err != nil {
+		return fmt.Errorf("error updating work day: %v", err)
+	}
+
+	return printResult(out, newWorkDayResult(workDay), "Updated work day #%d (%s).\n", workDay.Id, util.FormatDate(workDay.Date))
+}
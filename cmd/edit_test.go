@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunEditPeriodCmd(t *testing.T) {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(date))
+	testutil.AssertNoErr(t, err)
+
+	wp1 := model.NewWorkPeriod(workDay)
+	wp1.StartAt = date.Add(9 * time.Hour)
+	wp1.SetEndAt(date.Add(12 * time.Hour))
+	wp1, err = repo.CreateWorkPeriod(wp1)
+	testutil.AssertNoErr(t, err)
+
+	wp2 := model.NewWorkPeriod(workDay)
+	wp2.StartAt = date.Add(13 * time.Hour)
+	wp2.SetEndAt(date.Add(17 * time.Hour))
+	wp2, err = repo.CreateWorkPeriod(wp2)
+	testutil.AssertNoErr(t, err)
+
+	t.Run("updates start, end and note", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runEditPeriodCmd(out, repo, editPeriodCmdArgs{id: wp1.Id, startStr: "09:05", endStr: "12:30", note: "Fixed."})
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "Updated work period #1.\n")
+
+		got, err := repo.GetWorkPeriodById(wp1.Id)
+		testutil.AssertNoErr(t, err)
+
+		want := wp1
+		want.StartAt = date.Add(9*time.Hour + 5*time.Minute)
+		want.EndAt = sql.NullTime{Valid: true, Time: date.Add(12*time.Hour + 30*time.Minute)}
+		want.Note = sql.NullString{Valid: true, String: "Fixed."}
+		want.UpdatedAt = time.Now()
+		testutil.AssertEqualStructs(t, got, want)
+	})
+
+	t.Run("adds tags from the note", func(t *testing.T) {
+		err := runEditPeriodCmd(&bytes.Buffer{}, repo, editPeriodCmdArgs{id: wp1.Id, note: "Fixed +review."})
+		testutil.AssertNoErr(t, err)
+
+		got, err := repo.GetWorkPeriodById(wp1.Id)
+		testutil.AssertNoErr(t, err)
+
+		want := []string{"review"}
+		if !reflect.DeepEqual(got.Tags, want) {
+			t.Errorf("got tags %v, want %v", got.Tags, want)
+		}
+	})
+
+	t.Run("start after end", func(t *testing.T) {
+		err := runEditPeriodCmd(&bytes.Buffer{}, repo, editPeriodCmdArgs{id: wp1.Id, startStr: "12:45"})
+		if err != model.ErrEndBeforeStart {
+			t.Errorf("got error %v, want %v", err, model.ErrEndBeforeStart)
+		}
+	})
+
+	t.Run("overlapping periods", func(t *testing.T) {
+		err := runEditPeriodCmd(&bytes.Buffer{}, repo, editPeriodCmdArgs{id: wp2.Id, startStr: "12:00"})
+		if err == nil {
+			t.Fatal("Expected an error but got none")
+		}
+
+		got, err := repo.GetWorkPeriodById(wp2.Id)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, got, wp2)
+	})
+
+	t.Run("unknown period", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runEditPeriodCmd(out, repo, editPeriodCmdArgs{id: 99, note: "Nope."})
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "Unable to find work period #99.\n")
+	})
+}
+
+func TestRunEditDayCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(date))
+	testutil.AssertNoErr(t, err)
+
+	t.Run("updates length and note", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runEditDayCmd(out, repo, editDayCmdArgs{dateStr: "2023-09-01", lengthStr: "6h", note: "Short day."})
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "Updated work day #1 (2023-09-01).\n")
+
+		got, err := repo.GetWorkDayByDate(date)
+		testutil.AssertNoErr(t, err)
+
+		want := workDay
+		want.LengthMins = 6 * 60
+		want.Note = sql.NullString{Valid: true, String: "Short day."}
+		want.UpdatedAt = time.Now()
+		testutil.AssertEqualStructs(t, got, want)
+	})
+
+	t.Run("negative length", func(t *testing.T) {
+		err := runEditDayCmd(&bytes.Buffer{}, repo, editDayCmdArgs{dateStr: "2023-09-01", lengthStr: "-1h"})
+		if err == nil {
+			t.Error("Expected an error but got none")
+		}
+	})
+
+	t.Run("unknown day", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runEditDayCmd(out, repo, editDayCmdArgs{dateStr: "2023-09-02", lengthStr: "6h"})
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "No work day for 2023-09-02 yet.\n")
+	})
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/exchange"
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/spf13/cobra"
+)
+
+var exportFormats = []string{"csv", "ics", "json", "timeclock", "timewarrior"}
+
+// workDayWriter writes work days in an export format.
+type workDayWriter interface {
+	WriteWorkDay(workDay model.WorkDay) error
+	Flush() error
+}
+
+type exportCmdArgs struct {
+	dateRange dateRangeArgs
+	format    string
+	perDay    bool
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports work days to stdout",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := exportCmdArgs{
+			dateRange: mustGetDateRangeFlags(cmd),
+			format:    mustGetStringFlag(cmd, "format"),
+			perDay:    mustGetBoolFlag(cmd, "per-day"),
+		}
+
+		if err := runExportCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	addDateRangeFlags(exportCmd)
+	exportCmd.Flags().StringP("format", "f", "csv", fmt.Sprintf("export format (%s)", strings.Join(exportFormats, ", ")))
+	exportCmd.Flags().Bool("per-day", false, "write one row per work day instead of one per work period")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExportCmd(out io.Writer, repo *repository.Repo, args exportCmdArgs) error {
+	from, to, err := args.dateRange.resolve()
+	if err != nil {
+		return err
+	}
+
+	switch args.format {
+	case "json":
+		// Backups cover every work day unless a range is asked for.
+		if !args.dateRange.isSet() {
+			from, to = time.Time{}, time.Date(9999, 12, 31, 0, 0, 0, 0, time.Local)
+		}
+
+		return exportJSON(out, repo, from, to)
+	case "csv":
+		w := exchange.NewCSVWriter(out, args.perDay)
+		if err := w.WriteHeader(); err != nil {
+			return err
+		}
+
+		return exportWorkDays(w, repo, from, to)
+	case "ics":
+		return exportICS(out, repo, from, to)
+	case "timeclock", "timewarrior":
+		projects, err := repo.GetProjects(true)
+		if err != nil {
+			return fmt.Errorf("error loading projects: %v", err)
+		}
+
+		if args.format == "timeclock" {
+			return exportWorkDays(exchange.NewTimeclockWriter(out, projects), repo, from, to)
+		}
+
+		return exportWorkDays(exchange.NewTimewarriorWriter(out, projects), repo, from, to)
+	default:
+		return fmt.Errorf("unknown export format '%s', expected one of %s", args.format, strings.Join(exportFormats, ", "))
+	}
+}
+
+func exportWorkDays(w workDayWriter, repo *repository.Repo, from time.Time, to time.Time) error {
+	err := repo.EachWorkDayInRange(from, to, func(workDay model.WorkDay) error {
+		return w.WriteWorkDay(workDay)
+	})
+	if err != nil {
+		return fmt.Errorf("error exporting work days: %v", err)
+	}
+
+	return w.Flush()
+}
+
+func exportJSON(out io.Writer, repo *repository.Repo, from time.Time, to time.Time) error {
+	projects, err := repo.GetProjects(true)
+	if err != nil {
+		return fmt.Errorf("error loading projects: %v", err)
+	}
+
+	installId, err := repo.GetInstallId()
+	if err != nil {
+		return fmt.Errorf("error loading install ID: %v", err)
+	}
+
+	w := exchange.NewJSONWriter(out)
+	if err := w.WriteHeader(time.Now(), installId, projects); err != nil {
+		return err
+	}
+
+	err = repo.EachWorkDayInRange(from, to, func(workDay model.WorkDay) error {
+		return w.WriteWorkDay(workDay)
+	})
+	if err != nil {
+		return fmt.Errorf("error exporting work days: %v", err)
+	}
+
+	return w.Close()
+}
+
+func exportICS(out io.Writer, repo *repository.Repo, from time.Time, to time.Time) error {
+	installId, err := repo.GetInstallId()
+	if err != nil {
+		return fmt.Errorf("error loading install ID: %v", err)
+	}
+
+	w := exchange.NewICSWriter(out, installId)
+	if err := w.WriteHeader(); err != nil {
+		return err
+	}
+
+	err = repo.EachWorkDayInRange(from, to, func(workDay model.WorkDay) error {
+		return w.WriteWorkDay(workDay)
+	})
+	if err != nil {
+		return fmt.Errorf("error exporting work days: %v", err)
+	}
+
+	return w.Close()
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunExportCmdCSV(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	for _, day := range []int{1, 4, 30} {
+		date := time.Date(2023, 9, day, 0, 0, 0, 0, time.Local)
+		wd, err := repo.CreateWorkDay(model.NewWorkDay(date))
+		testutil.AssertNoErr(t, err)
+
+		wp := model.NewWorkPeriod(wd)
+		wp.StartAt = date.Add(9 * time.Hour)
+		wp.SetEndAt(date.Add(17 * time.Hour))
+		_, err = repo.CreateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+	}
+
+	t.Run("per work period", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		args := exportCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-29"}, format: "csv"}
+		err := runExportCmd(out, repo, args)
+		testutil.AssertNoErr(t, err)
+
+		testutil.AssertOutput(t, out, `date,start,end,duration_mins,break_mins,note,day_length_mins
+2023-09-01,2023-09-01 09:00,2023-09-01 17:00,480,0,,450
+2023-09-04,2023-09-04 09:00,2023-09-04 17:00,480,0,,450
+`)
+	})
+
+	t.Run("per work day", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		args := exportCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-04", toStr: "2023-09-30"}, format: "csv", perDay: true}
+		err := runExportCmd(out, repo, args)
+		testutil.AssertNoErr(t, err)
+
+		testutil.AssertOutput(t, out, `date,day_length_mins,worked_mins,break_mins,balance_mins,note
+2023-09-04,450,480,0,30,
+2023-09-30,450,480,0,30,
+`)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		err := runExportCmd(&bytes.Buffer{}, repo, exportCmdArgs{format: "xls"})
+		if err == nil {
+			t.Error("Expected an error but got none")
+		}
+	})
+}
+
+func TestRunExportCmdICS(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	wd, err := repo.CreateWorkDay(model.NewWorkDay(date))
+	testutil.AssertNoErr(t, err)
+
+	closed := model.NewWorkPeriod(wd)
+	closed.StartAt = date.Add(9 * time.Hour)
+	closed.SetEndAt(date.Add(12 * time.Hour))
+	closed.SetNote("Planning")
+	closed, err = repo.CreateWorkPeriod(closed)
+	testutil.AssertNoErr(t, err)
+
+	open := model.NewWorkPeriod(wd)
+	open.StartAt = date.Add(13 * time.Hour)
+	_, err = repo.CreateWorkPeriod(open)
+	testutil.AssertNoErr(t, err)
+
+	export := func() string {
+		out := &bytes.Buffer{}
+		args := exportCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-01"}, format: "ics"}
+		testutil.AssertNoErr(t, runExportCmd(out, repo, args))
+		return out.String()
+	}
+
+	installId, err := repo.GetInstallId()
+	testutil.AssertNoErr(t, err)
+	uid := fmt.Sprintf("UID:work-period-1-%s@wh\r\n", installId)
+
+	first := export()
+	if got := strings.Count(first, "BEGIN:VEVENT"); got != 1 {
+		t.Errorf("Expected only the closed work period as an event, got %d events", got)
+	}
+	if !strings.Contains(first, uid) || !strings.Contains(first, "SUMMARY:Planning\r\n") {
+		t.Errorf("Unexpected event:\n%s", first)
+	}
+
+	// Updating the work period keeps its UID.
+	closed.SetNote("Sprint planning")
+	_, err = repo.UpdateWorkPeriod(closed)
+	testutil.AssertNoErr(t, err)
+
+	second := export()
+	if !strings.Contains(second, uid) || !strings.Contains(second, "SUMMARY:Sprint planning\r\n") {
+		t.Errorf("Unexpected event after update:\n%s", second)
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/robyparr/wh/exchange"
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/spf13/cobra"
+)
+
+var importFormats = append([]string{"json", "timeclock", "timewarrior"}, exchange.TimesheetFormats...)
+
+type importCmdArgs struct {
+	format     string
+	onConflict string
+	dryRun     bool
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Imports work days from a file",
+	Long: `Imports work days from a file. Everything in the file is imported in one
+transaction, so an error leaves the database untouched.
+
+Work days whose date already exists are handled according to --on-conflict:
+skip leaves the existing work day alone, overwrite replaces it and merge adds
+the work periods and breaks that don't overlap the existing ones.
+
+Entries of timeclock, Timewarrior, Toggl, Clockify and Harvest files that can't
+be imported are rejected and listed rather than failing the import.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			fatal(err)
+		}
+		defer file.Close()
+
+		cmdArgs := importCmdArgs{
+			format:     mustGetStringFlag(cmd, "from"),
+			onConflict: mustGetStringFlag(cmd, "on-conflict"),
+			dryRun:     mustGetBoolFlag(cmd, "dry-run"),
+		}
+
+		if err := runImportCmd(file, os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	conflicts := make([]string, 0, len(repository.ImportConflicts))
+	for _, conflict := range repository.ImportConflicts {
+		conflicts = append(conflicts, string(conflict))
+	}
+
+	importCmd.Flags().String("from", "json", fmt.Sprintf("import format (%s)", strings.Join(importFormats, ", ")))
+	importCmd.Flags().String("on-conflict", "skip", fmt.Sprintf("what to do with work days that already exist (%s)", strings.Join(conflicts, ", ")))
+	importCmd.Flags().Bool("dry-run", false, "show what would be imported without saving anything")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImportCmd(in io.Reader, out io.Writer, repo *repository.Repo, args importCmdArgs) error {
+	conflict := repository.ImportConflict(args.onConflict)
+	if !isImportConflict(conflict) {
+		return fmt.Errorf("unknown conflict handling '%s'", args.onConflict)
+	}
+
+	var installId string
+	var projects []model.Project
+	var workDays []model.WorkDay
+	var rejections []exchange.Rejection
+	var err error
+
+	switch args.format {
+	case "json":
+		var backup exchange.Backup
+		backup, err = exchange.ReadJSON(in)
+		installId, projects, workDays = backup.InstallId, backup.Projects, backup.WorkDays
+	case "timeclock":
+		workDays, rejections, err = exchange.ReadTimeclock(in)
+	case "timewarrior":
+		workDays, rejections, err = exchange.ReadTimewarrior(in)
+	case "toggl", "clockify", "harvest":
+		workDays, rejections, err = exchange.ReadTimesheetCSV(in, args.format)
+	default:
+		return fmt.Errorf("unknown import format '%s', expected one of %s", args.format, strings.Join(importFormats, ", "))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Only backups record the length of work days, for other formats it comes
+	// from the schedule or config like it would for new work days.
+	if args.format != "json" {
+		for i, workDay := range workDays {
+			newDay, err := newWorkDay(repo, workDay.Date)
+			if err != nil {
+				return err
+			}
+
+			workDays[i].LengthMins = newDay.LengthMins
+		}
+	}
+
+	result, err := repo.ImportWorkDays(installId, projects, workDays, conflict, args.dryRun)
+	if err != nil {
+		return fmt.Errorf("error importing work days: %v", err)
+	}
+
+	vm := importResult{
+		DryRun:          args.dryRun,
+		DaysCreated:     result.DaysCreated,
+		DaysOverwritten: result.DaysOverwritten,
+		DaysMerged:      result.DaysMerged,
+		DaysSkipped:     result.DaysSkipped,
+		PeriodsCreated:  result.PeriodsCreated,
+		PeriodsSkipped:  result.PeriodsSkipped,
+		BreaksCreated:   result.BreaksCreated,
+		BreaksSkipped:   result.BreaksSkipped,
+		IdsChanged:      result.IdsChanged,
+		Warnings:        []string{},
+		Rejections:      []string{},
+	}
+
+	// Harvest start times are made up, so they may clash with real ones.
+	if args.format == "harvest" {
+		vm.Warnings = append(vm.Warnings, "Harvest only exports hours, so start times were laid out from 9:00 and may have been skipped as overlapping existing work periods")
+	}
+
+	var text strings.Builder
+	if args.dryRun {
+		fmt.Fprintln(&text, "Dry run, nothing was saved.")
+	}
+
+	fmt.Fprintf(&text, "Work days: %d created, %d overwritten, %d merged, %d skipped.\n", result.DaysCreated, result.DaysOverwritten, result.DaysMerged, result.DaysSkipped)
+	fmt.Fprintf(&text, "Work periods: %d created, %d skipped as overlapping.\n", result.PeriodsCreated, result.PeriodsSkipped)
+	fmt.Fprintf(&text, "Breaks: %d created, %d skipped as overlapping.\n", result.BreaksCreated, result.BreaksSkipped)
+	if result.IdsChanged > 0 {
+		fmt.Fprintf(&text, "New IDs: %d, as their IDs were already taken.\n", result.IdsChanged)
+	}
+
+	for _, warning := range vm.Warnings {
+		fmt.Fprintf(&text, "Warning: %s.\n", warning)
+	}
+
+	if len(rejections) > 0 {
+		fmt.Fprintf(&text, "Rejected: %d.\n", len(rejections))
+		for _, rejection := range rejections {
+			vm.Rejections = append(vm.Rejections, rejection.String())
+			fmt.Fprintf(&text, "  %s\n", rejection)
+		}
+	}
+
+	return printResult(out, vm, "%s", text.String())
+}
+
+func isImportConflict(conflict repository.ImportConflict) bool {
+	for _, c := range repository.ImportConflicts {
+		if c == conflict {
+			return true
+		}
+	}
+
+	return false
+}
+
+// importResult counts what an import did, or would do on a dry run, and lists
+// the rejected entries of the file.
+type importResult struct {
+	DryRun          bool     `json:"dry_run"`
+	DaysCreated     int      `json:"days_created"`
+	DaysOverwritten int      `json:"days_overwritten"`
+	DaysMerged      int      `json:"days_merged"`
+	DaysSkipped     int      `json:"days_skipped"`
+	PeriodsCreated  int      `json:"periods_created"`
+	PeriodsSkipped  int      `json:"periods_skipped"`
+	BreaksCreated   int      `json:"breaks_created"`
+	BreaksSkipped   int      `json:"breaks_skipped"`
+	IdsChanged      int      `json:"ids_changed"`
+	Warnings        []string `json:"warnings"`
+	Rejections      []string `json:"rejections"`
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/config"
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunImportCmd(t *testing.T) {
+	source := testutil.NewRepo(t)
+
+	project, err := source.CreateProject(model.NewProject("acme"))
+	testutil.AssertNoErr(t, err)
+
+	for _, day := range []int{1, 4} {
+		date := time.Date(2023, 9, day, 0, 0, 0, 0, time.Local)
+		wd, err := source.CreateWorkDay(model.NewWorkDay(date))
+		testutil.AssertNoErr(t, err)
+
+		wp := model.NewWorkPeriod(wd)
+		wp.StartAt = date.Add(9 * time.Hour)
+		wp.SetEndAt(date.Add(17 * time.Hour))
+		wp.SetProject(project)
+		wp.AddTags("billing")
+		_, err = source.CreateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+
+		b := model.NewBreak(wd)
+		b.StartAt = date.Add(12 * time.Hour)
+		b.SetEndAt(date.Add(12*time.Hour + 30*time.Minute))
+		_, err = source.CreateBreak(b)
+		testutil.AssertNoErr(t, err)
+	}
+
+	// Without a date range a JSON export covers every work day.
+	backup := &bytes.Buffer{}
+	err = runExportCmd(backup, source, exportCmdArgs{format: "json"})
+	testutil.AssertNoErr(t, err)
+
+	repo := testutil.NewRepo(t)
+
+	t.Run("dry run", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		args := importCmdArgs{format: "json", onConflict: "skip", dryRun: true}
+		err := runImportCmd(bytes.NewReader(backup.Bytes()), out, repo, args)
+		testutil.AssertNoErr(t, err)
+
+		testutil.AssertOutput(t, out, `Dry run, nothing was saved.
+Work days: 2 created, 0 overwritten, 0 merged, 0 skipped.
+Work periods: 2 created, 0 skipped as overlapping.
+Breaks: 2 created, 0 skipped as overlapping.
+`)
+
+		count, err := repo.GetWorkDayCount()
+		testutil.AssertNoErr(t, err)
+		if count != 0 {
+			t.Errorf("Expected no work days, got %d", count)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		args := importCmdArgs{format: "json", onConflict: "skip"}
+		err := runImportCmd(bytes.NewReader(backup.Bytes()), out, repo, args)
+		testutil.AssertNoErr(t, err)
+
+		testutil.AssertOutput(t, out, `Work days: 2 created, 0 overwritten, 0 merged, 0 skipped.
+Work periods: 2 created, 0 skipped as overlapping.
+Breaks: 2 created, 0 skipped as overlapping.
+`)
+
+		workDays, err := repo.GetWorkDaysInRange(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local), time.Date(2023, 9, 30, 0, 0, 0, 0, time.Local))
+		testutil.AssertNoErr(t, err)
+		if len(workDays) != 2 {
+			t.Fatalf("Expected 2 work days, got %d", len(workDays))
+		}
+
+		got := workDays[1]
+		if got.TimeWorked() != 7*time.Hour+30*time.Minute {
+			t.Errorf("Expected 7h30m worked, got %s", got.TimeWorked())
+		}
+
+		period := got.WorkPeriods()[0]
+		if len(period.Tags) != 1 || period.Tags[0] != "billing" || !period.ProjectId.Valid {
+			t.Errorf("Expected the project and tags to be imported, got %+v", period)
+		}
+
+		// A restored database exports the same calendar events.
+		exportICS := func(repo *repository.Repo) string {
+			out := &bytes.Buffer{}
+			args := exportCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-30"}, format: "ics"}
+			testutil.AssertNoErr(t, runExportCmd(out, repo, args))
+			return out.String()
+		}
+		if want, got := exportICS(source), exportICS(repo); got != want {
+			t.Errorf("Expected the same calendar as the source database, got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("import again", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		args := importCmdArgs{format: "json", onConflict: "merge"}
+		err := runImportCmd(bytes.NewReader(backup.Bytes()), out, repo, args)
+		testutil.AssertNoErr(t, err)
+
+		testutil.AssertOutput(t, out, `Work days: 0 created, 0 overwritten, 2 merged, 0 skipped.
+Work periods: 0 created, 2 skipped as overlapping.
+Breaks: 0 created, 2 skipped as overlapping.
+`)
+	})
+
+	t.Run("invalid args", func(t *testing.T) {
+		tests := []importCmdArgs{
+			{format: "json", onConflict: "replace"},
+			{format: "xls", onConflict: "skip"},
+		}
+
+		for _, args := range tests {
+			err := runImportCmd(strings.NewReader(backup.String()), &bytes.Buffer{}, repo, args)
+			if err == nil {
+				t.Errorf("Expected an error for %+v but got none", args)
+			}
+		}
+	})
+}
+
+func TestRunImportCmdTimeclock(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	prevCfg := cfg
+	t.Cleanup(func() { cfg = prevCfg })
+	cfg.DayLength = config.Duration(8 * time.Hour)
+
+	input := `i 2023/09/01 09:00:00 acme  Invoices
+o 2023/09/01 12:00:00
+i 2023/09/01 13:00:00 acme
+o 2023/09/01 17:00:00
+o 2023/09/01 17:30:00
+`
+
+	out := &bytes.Buffer{}
+	err := runImportCmd(strings.NewReader(input), out, repo, importCmdArgs{format: "timeclock", onConflict: "skip"})
+	testutil.AssertNoErr(t, err)
+
+	testutil.AssertOutput(t, out, `Work days: 1 created, 0 overwritten, 0 merged, 0 skipped.
+Work periods: 2 created, 0 skipped as overlapping.
+Breaks: 0 created, 0 skipped as overlapping.
+Rejected: 1.
+  line 5: clock-out without a clock-in
+`)
+
+	workDay, err := repo.GetWorkDayByDate(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local))
+	testutil.AssertNoErr(t, err)
+	if workDay.LengthMins != 8*60 {
+		t.Errorf("Expected the configured day length, got %d mins", workDay.LengthMins)
+	}
+
+	// Exporting gives back what was imported.
+	export := &bytes.Buffer{}
+	args := exportCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-01"}, format: "timeclock"}
+	testutil.AssertNoErr(t, runExportCmd(export, repo, args))
+
+	testutil.AssertOutput(t, export, `i 2023/09/01 09:00:00 work  acme: Invoices
+o 2023/09/01 12:00:00
+i 2023/09/01 13:00:00 work  acme
+o 2023/09/01 17:00:00
+`)
+}
+
+func TestRunImportCmdToggl(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	sep1 := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	_, err := repo.CreateWorkDay(model.NewWorkDay(sep1))
+	testutil.AssertNoErr(t, err)
+
+	input := `User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()
+Sam,sam@example.com,Acme,Website,,Fix login,Yes,2023-09-01,09:00:00,2023-09-01,11:30:00,02:30:00,,
+Sam,sam@example.com,Acme,Website,,Deploy,Yes,2023-09-04,09:00:00,2023-09-04,10:00:00,01:00:00,,
+Sam,sam@example.com,Acme,Website,,Review,Yes,2023-09-04,13:00:00,2023-09-04,15:00:00,02:00:00,,
+Sam,sam@example.com,Acme,Website,,Typo,Yes,2023-09-05,09:00:00,,,01:00:00,,
+`
+
+	out := &bytes.Buffer{}
+	err = runImportCmd(strings.NewReader(input), out, repo, importCmdArgs{format: "toggl", onConflict: "merge"})
+	testutil.AssertNoErr(t, err)
+
+	testutil.AssertOutput(t, out, `Work days: 1 created, 0 overwritten, 1 merged, 0 skipped.
+Work periods: 3 created, 0 skipped as overlapping.
+Breaks: 0 created, 0 skipped as overlapping.
+Rejected: 1.
+  line 5: invalid end: invalid date ''
+`)
+
+	workDay, err := repo.GetWorkDayByDate(sep1.AddDate(0, 0, 3))
+	testutil.AssertNoErr(t, err)
+	periods, err := repo.GetWorkPeriods(workDay)
+	testutil.AssertNoErr(t, err)
+	if len(periods) != 2 {
+		t.Errorf("Expected both rows on Sep 4 on one work day, got %d work periods", len(periods))
+	}
+}
+
+func TestRunImportCmdHarvest(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	sep1 := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	wd, err := repo.CreateWorkDay(model.NewWorkDay(sep1))
+	testutil.AssertNoErr(t, err)
+
+	wp := model.NewWorkPeriod(wd)
+	wp.StartAt = sep1.Add(9 * time.Hour)
+	wp.SetEndAt(sep1.Add(10 * time.Hour))
+	_, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	input := `Date,Client,Project,Project Code,Task,Notes,Hours,Hours Rounded,Billable?
+2023-09-01,Acme,Website,,Development,Fix login,2.5,2.5,Yes
+`
+
+	out := &bytes.Buffer{}
+	err = runImportCmd(strings.NewReader(input), out, repo, importCmdArgs{format: "harvest", onConflict: "merge"})
+	testutil.AssertNoErr(t, err)
+
+	testutil.AssertOutput(t, out, `Work days: 0 created, 0 overwritten, 1 merged, 0 skipped.
+Work periods: 0 created, 1 skipped as overlapping.
+Breaks: 0 created, 0 skipped as overlapping.
+Warning: Harvest only exports hours, so start times were laid out from 9:00 and may have been skipped as overlapping existing work periods.
+`)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+type listCmdArgs struct {
+	dateRange dateRangeArgs
+	tags      []string
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists work days over a date range",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := listCmdArgs{
+			dateRange: mustGetDateRangeFlags(cmd),
+			tags:      mustGetStringArrayFlag(cmd, "tag"),
+		}
+
+		if err := runListCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	addDateRangeFlags(listCmd)
+	listCmd.Flags().StringArrayP("tag", "t", nil, "only count work periods with this tag, can be repeated")
+	rootCmd.AddCommand(listCmd)
+}
+
+func runListCmd(out io.Writer, repo *repository.Repo, args listCmdArgs) error {
+	from, to, err := args.dateRange.resolve()
+	if err != nil {
+		return err
+	}
+
+	workDays, err := repo.GetWorkDaysInRange(from, to)
+	if err != nil {
+		return fmt.Errorf("error loading work days: %v", err)
+	}
+	workDays = filterWorkDaysByTags(workDays, args.tags)
+
+	if len(workDays) == 0 {
+		return printResult(out, newListResult(from, to, workDays), "No work days between %s and %s.\n", util.FormatDate(from), util.FormatDate(to))
+	}
+
+	vm := listViewModel{
+		Title: fmt.Sprintf("%s to %s", util.FormatDate(from), util.FormatDate(to)),
+	}
+
+	var totalLength, totalWorked time.Duration
+	for _, wd := range workDays {
+		totalLength += wd.Length()
+		totalWorked += wd.TimeWorked()
+
+		vm.WorkDays = append(vm.WorkDays, listDayViewModel{
+			Date:       util.FormatDate(wd.Date) + wd.Date.Format(" Mon"),
+			DayLength:  util.FormatDuration(wd.Length()),
+			TimeWorked: util.FormatDuration(wd.TimeWorked()),
+			Balance:    util.FormatBalance(wd.Balance()),
+			Note:       wd.Note.String,
+		})
+	}
+
+	vm.DayLength = util.FormatDuration(totalLength)
+	vm.TimeWorked = util.FormatDuration(totalWorked)
+	vm.Balance = util.FormatBalance(totalWorked - totalLength)
+
+	return renderResult(out, "work_day_list.txt", vm, newListResult(from, to, workDays))
+}
+
+type listViewModel struct {
+	Title      string
+	DayLength  string
+	TimeWorked string
+	Balance    string
+	WorkDays   []listDayViewModel
+}
+
+type listDayViewModel struct {
+	Date       string
+	DayLength  string
+	TimeWorked string
+	Balance    string
+	Note       string
+}
+
+// listResult is the work days listed by list in the json and tsv output
+// formats, with the work days as the tsv rows.
+type listResult struct {
+	From           string          `json:"from"`
+	To             string          `json:"to"`
+	LengthMins     int             `json:"length_mins"`
+	TimeWorkedMins int             `json:"time_worked_mins"`
+	BalanceMins    int             `json:"balance_mins"`
+	WorkDays       []listDayResult `json:"work_days" tsv:"rows"`
+}
+
+type listDayResult struct {
+	workDayResult
+	LengthMins     int    `json:"length_mins"`
+	TimeWorkedMins int    `json:"time_worked_mins"`
+	BalanceMins    int    `json:"balance_mins"`
+	Note           string `json:"note"`
+}
+
+func newListResult(from time.Time, to time.Time, workDays []model.WorkDay) listResult {
+	result := listResult{
+		From:     from.Format(util.DateFormatStr),
+		To:       to.Format(util.DateFormatStr),
+		WorkDays: []listDayResult{},
+	}
+
+	var totalLength, totalWorked time.Duration
+	for _, wd := range workDays {
+		totalLength += wd.Length()
+		totalWorked += wd.TimeWorked()
+
+		result.WorkDays = append(result.WorkDays, listDayResult{
+			workDayResult:  newWorkDayResult(wd),
+			LengthMins:     wd.LengthMins,
+			TimeWorkedMins: int(wd.TimeWorked().Minutes()),
+			BalanceMins:    int(wd.Balance().Minutes()),
+			Note:           wd.Note.String,
+		})
+	}
+
+	result.LengthMins = int(totalLength.Minutes())
+	result.TimeWorkedMins = int(totalWorked.Minutes())
+	result.BalanceMins = int((totalWorked - totalLength).Minutes())
+	return result
+}
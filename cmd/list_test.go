@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunListCmdNoWorkDays(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runListCmd(out, repo, listCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-30"}})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "No work days between 2023-09-01 and 2023-09-30.\n")
+}
+
+func TestRunListCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	wd1 := model.NewWorkDay(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local))
+	wd1.SetNote("First day.")
+	wd1, err := repo.CreateWorkDay(wd1)
+	testutil.AssertNoErr(t, err)
+
+	wp := model.NewWorkPeriod(wd1)
+	wp.StartAt = time.Date(2023, 9, 1, 9, 0, 0, 0, time.Local)
+	wp.SetEndAt(wp.StartAt.Add(8 * time.Hour))
+	_, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	wd2, err := repo.CreateWorkDay(model.NewWorkDay(time.Date(2023, 9, 4, 0, 0, 0, 0, time.Local)))
+	testutil.AssertNoErr(t, err)
+
+	wp = model.NewWorkPeriod(wd2)
+	wp.StartAt = time.Date(2023, 9, 4, 9, 0, 0, 0, time.Local)
+	wp.SetEndAt(wp.StartAt.Add(6 * time.Hour))
+	_, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	_, err = repo.CreateWorkDay(model.NewWorkDay(time.Date(2023, 10, 2, 0, 0, 0, 0, time.Local)))
+	testutil.AssertNoErr(t, err)
+
+	out := &bytes.Buffer{}
+	err = runListCmd(out, repo, listCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-30"}})
+	testutil.AssertNoErr(t, err)
+
+	compareShowOutput(
+		t,
+		out.String(),
+		`
+2023-09-01 to 2023-09-30
+========================
+
+DATE		DAY LENGTH	TIME WORKED	BALANCE	NOTE
+2023-09-01 Fri	7h30m		8h0m		+30m	First day.
+2023-09-04 Mon	7h30m		6h0m		-1h30m:tab
+
+TOTAL		15h0m		14h0m		-1h0m
+`,
+		map[string]string{"tab": "	"},
+	)
+}
+
+func TestRunListCmdWithTags(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	wd1, err := repo.CreateWorkDay(model.NewWorkDay(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)))
+	testutil.AssertNoErr(t, err)
+
+	wp := model.NewWorkPeriod(wd1)
+	wp.StartAt = time.Date(2023, 9, 1, 9, 0, 0, 0, time.Local)
+	wp.SetEndAt(wp.StartAt.Add(2 * time.Hour))
+	wp.AddTags("meeting")
+	_, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	wp = model.NewWorkPeriod(wd1)
+	wp.StartAt = time.Date(2023, 9, 1, 11, 0, 0, 0, time.Local)
+	wp.SetEndAt(wp.StartAt.Add(4 * time.Hour))
+	_, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	wd2, err := repo.CreateWorkDay(model.NewWorkDay(time.Date(2023, 9, 4, 0, 0, 0, 0, time.Local)))
+	testutil.AssertNoErr(t, err)
+
+	wp = model.NewWorkPeriod(wd2)
+	wp.StartAt = time.Date(2023, 9, 4, 9, 0, 0, 0, time.Local)
+	wp.SetEndAt(wp.StartAt.Add(6 * time.Hour))
+	wp.AddTags("admin")
+	_, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	out := &bytes.Buffer{}
+	args := listCmdArgs{
+		dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-30"},
+		tags:      []string{"meeting"},
+	}
+	err = runListCmd(out, repo, args)
+	testutil.AssertNoErr(t, err)
+
+	compareShowOutput(
+		t,
+		out.String(),
+		`
+2023-09-01 to 2023-09-30
+========================
+
+DATE		DAY LENGTH	TIME WORKED	BALANCE	NOTE
+2023-09-01 Fri	7h30m		2h0m		-5h30m:tab
+
+TOTAL		7h30m		2h0m		-5h30m
+`,
+		map[string]string{"tab": "	"},
+	)
+}
+
+func TestDateRangeArgsResolve(t *testing.T) {
+	date := func(day int) time.Time {
+		return time.Date(2023, 9, day, 0, 0, 0, 0, time.Local)
+	}
+
+	testCases := []struct {
+		name     string
+		args     dateRangeArgs
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{name: "from and to", args: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-30"}, wantFrom: date(1), wantTo: date(30)},
+		{name: "same day", args: dateRangeArgs{fromStr: "2023-09-14", toStr: "2023-09-14"}, wantFrom: date(14), wantTo: date(14)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotFrom, gotTo, err := tc.args.resolve()
+			testutil.AssertNoErr(t, err)
+			testutil.AssertAroundTime(t, "from", gotFrom, tc.wantFrom)
+			testutil.AssertAroundTime(t, "to", gotTo, tc.wantTo)
+		})
+	}
+
+	t.Run("from after to", func(t *testing.T) {
+		_, _, err := dateRangeArgs{fromStr: "2023-09-30", toStr: "2023-09-01"}.resolve()
+		if err == nil {
+			t.Error("Expected an error but got none")
+		}
+	})
+}
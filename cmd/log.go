@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+type logCmdArgs struct {
+	dateStr   string
+	rangeStrs []string
+	lengthStr string
+	note      string
+	dayNote   string
+	project   string
+	tags      []string
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log <date> <start-end>...",
+	Short: "Log work periods on a past day in one step",
+	Long: `Log work periods on a past day in one step, e.g.
+
+  wh log 2023-09-14 09:00-12:00 13:00-17:30 --note "Billing API"
+
+The work day is created if it doesn't exist yet. Either every work period is
+logged or, if any of them is invalid or overlaps another, none are.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := logCmdArgs{
+			dateStr:   args[0],
+			rangeStrs: args[1:],
+			lengthStr: mustGetStringFlag(cmd, "length"),
+			note:      mustGetStringFlag(cmd, "note"),
+			dayNote:   mustGetStringFlag(cmd, "day-note"),
+			project:   mustGetStringFlag(cmd, "project"),
+			tags:      mustGetStringArrayFlag(cmd, "tag"),
+		}
+
+		if err := runLogCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	logCmd.Flags().StringP("length", "l", "", "work day length if the work day is new (e.g. 4h30m)")
+	logCmd.Flags().StringP("note", "n", "", "note for every work period")
+	logCmd.Flags().StringP("day-note", "d", "", "work day note")
+	logCmd.Flags().StringP("project", "p", "", "project to attribute the work periods to (default default_project from the config file)")
+	logCmd.Flags().StringArrayP("tag", "t", nil, "tag for every work period, can be repeated (or write +tag in the note)")
+
+	rootCmd.AddCommand(logCmd)
+}
+
+func runLogCmd(out io.Writer, repo *repository.Repo, args logCmdArgs) error {
+	date, err := util.ParseDateString(args.dateStr)
+	if err != nil {
+		return fmt.Errorf("error parsing date: %v", err)
+	}
+
+	project, err := resolveProject(repo, args.project)
+	if err != nil {
+		return err
+	}
+
+	workDay, err := repo.GetWorkDayByDate(date)
+	if err != nil {
+		return fmt.Errorf("error loading work day: %v", err)
+	}
+
+	existing, err := repo.GetWorkPeriods(workDay)
+	if err != nil {
+		return fmt.Errorf("error loading work periods: %v", err)
+	}
+
+	var periods []model.WorkPeriod
+	for _, rangeStr := range args.rangeStrs {
+		period, err := parseTimeRange(rangeStr, date)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range append(existing, periods...) {
+			if period.Overlaps(other) {
+				return fmt.Errorf("time range '%s' would overlap with another work period on %s", rangeStr, util.FormatDate(date))
+			}
+		}
+
+		if args.note != "" {
+			period.SetNote(args.note)
+		}
+		period.SetProject(project)
+		period.AddTags(args.tags...)
+		period.AddTags(model.ParseNoteTags(args.note)...)
+
+		periods = append(periods, period)
+	}
+
+	if workDay.Id == 0 {
+		workDay, err = newWorkDay(repo, date)
+		if err != nil {
+			return err
+		}
+
+		if args.lengthStr != "" {
+			duration, err := time.ParseDuration(args.lengthStr)
+			if err != nil {
+				return fmt.Errorf("error parsing length string: %v", err)
+			}
+
+			workDay.LengthMins = int(duration.Minutes())
+		}
+
+		if args.dayNote != "" {
+			workDay.SetNote(args.dayNote)
+		}
+	}
+
+	workDay, periods, err = repo.LogWorkDay(workDay, periods)
+	if err != nil {
+		return fmt.Errorf("error logging work periods: %v", err)
+	}
+
+	var timeWorked time.Duration
+	result := logResult{workDayResult: newWorkDayResult(workDay)}
+	for _, wp := range periods {
+		timeWorked += wp.TimeWorked()
+		result.WorkPeriods = append(result.WorkPeriods, newWorkPeriodResult(wp))
+	}
+
+	return printResult(out, result, "Logged %d work period(s) (%s) on work day #%d (%s).\n", len(periods), util.FormatDuration(timeWorked), workDay.Id, util.FormatDate(workDay.Date))
+}
+
+// parseTimeRange parses a "09:00-12:00" style range into a closed work period
+// on the date.
+func parseTimeRange(rangeStr string, date time.Time) (model.WorkPeriod, error) {
+	startStr, endStr, found := strings.Cut(rangeStr, "-")
+	if !found || startStr == "" || endStr == "" {
+		return model.WorkPeriod{}, fmt.Errorf("invalid time range '%s', expected START-END (e.g. 09:00-12:00)", rangeStr)
+	}
+
+	startAt, err := util.ParseClockTimeOn(startStr, date)
+	if err != nil {
+		return model.WorkPeriod{}, fmt.Errorf("error parsing time range '%s': %v", rangeStr, err)
+	}
+
+	endAt, err := util.ParseClockTimeOn(endStr, date)
+	if err != nil {
+		return model.WorkPeriod{}, fmt.Errorf("error parsing time range '%s': %v", rangeStr, err)
+	}
+
+	period := model.WorkPeriod{StartAt: startAt}
+	period.SetEndAt(endAt)
+	if err := period.Validate(); err != nil {
+		return model.WorkPeriod{}, fmt.Errorf("invalid time range '%s': %v", rangeStr, err)
+	}
+
+	return period, nil
+}
+
+// logResult is the work day periods were logged on and the logged work
+// periods.
+type logResult struct {
+	workDayResult
+	WorkPeriods []workPeriodResult `json:"work_periods" tsv:"rows"`
+}
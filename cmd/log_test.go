@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunLogCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 14, 0, 0, 0, 0, time.Local)
+
+	out := &bytes.Buffer{}
+	args := logCmdArgs{
+		dateStr:   "2023-09-14",
+		rangeStrs: []string{"09:00-12:00", "13:00-17:30"},
+		lengthStr: "8h",
+		note:      "Billing API +backend",
+	}
+	err := runLogCmd(out, repo, args)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Logged 2 work period(s) (7h30m) on work day #1 (2023-09-14).\n")
+
+	workDay, err := repo.GetWorkDayByDate(date)
+	testutil.AssertNoErr(t, err)
+	if workDay.LengthMins != 480 {
+		t.Errorf("got work day length %d, want 480", workDay.LengthMins)
+	}
+
+	periods, err := repo.GetWorkPeriods(workDay)
+	testutil.AssertNoErr(t, err)
+	if len(periods) != 2 {
+		t.Fatalf("got %d work periods, want 2", len(periods))
+	}
+
+	if want := date.Add(13 * time.Hour); !periods[1].StartAt.Equal(want) {
+		t.Errorf("got start %v, want %v", periods[1].StartAt, want)
+	}
+
+	if want := date.Add(17*time.Hour + 30*time.Minute); !periods[1].EndAt.Valid || !periods[1].EndAt.Time.Equal(want) {
+		t.Errorf("got end %v, want %v", periods[1].EndAt, want)
+	}
+
+	testutil.AssertEqualStructs(t, periods[1].Note, sql.NullString{Valid: true, String: "Billing API +backend"})
+	if !reflect.DeepEqual(periods[1].Tags, []string{"backend"}) {
+		t.Errorf("got tags %v, want %v", periods[1].Tags, []string{"backend"})
+	}
+
+	t.Run("adds to an existing work day", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runLogCmd(out, repo, logCmdArgs{dateStr: "2023-09-14", rangeStrs: []string{"17:30-18:00"}})
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "Logged 1 work period(s) (30m) on work day #1 (2023-09-14).\n")
+	})
+}
+
+func TestRunLogCmdInvalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rangeStrs []string
+	}{
+		{name: "missing end", rangeStrs: []string{"09:00"}},
+		{name: "end before start", rangeStrs: []string{"12:00-09:00"}},
+		{name: "overlapping ranges", rangeStrs: []string{"09:00-12:00", "11:00-13:00"}},
+		{name: "time relative to now", rangeStrs: []string{"now-17:00"}},
+		{name: "overlapping an existing period", rangeStrs: []string{"15:00-16:00"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := testutil.NewRepo(t)
+			workDay, err := repo.CreateWorkDay(model.NewWorkDay(time.Date(2023, 9, 14, 0, 0, 0, 0, time.Local)))
+			testutil.AssertNoErr(t, err)
+
+			existing := model.NewWorkPeriod(workDay)
+			existing.StartAt = workDay.Date.Add(14 * time.Hour)
+			existing.SetEndAt(workDay.Date.Add(16 * time.Hour))
+			_, err = repo.CreateWorkPeriod(existing)
+			testutil.AssertNoErr(t, err)
+
+			err = runLogCmd(&bytes.Buffer{}, repo, logCmdArgs{dateStr: "2023-09-14", rangeStrs: tc.rangeStrs})
+			if err == nil {
+				t.Fatal("Expected an error but got none")
+			}
+
+			periods, err := repo.GetWorkPeriods(workDay)
+			testutil.AssertNoErr(t, err)
+			if len(periods) != 1 {
+				t.Errorf("got %d work periods, want only the existing one", len(periods))
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/template"
+	"github.com/robyparr/wh/util"
+)
+
+// outputFormat is the --output flag, which overrides the output setting of
+// the config file when given.
+var outputFormat string
+
+// messageResult is what commands without a result of their own, such as when
+// there is nothing to show, print in the json and tsv output formats.
+type messageResult struct {
+	Message string `json:"message"`
+}
+
+// workDayResult identifies a work day in the json and tsv output formats.
+type workDayResult struct {
+	Id   int    `json:"id"`
+	Date string `json:"date"`
+}
+
+// workPeriodResult describes a work period in the json and tsv output
+// formats. Open work periods have an empty end.
+type workPeriodResult struct {
+	Id        int      `json:"id"`
+	WorkDayId int      `json:"work_day_id"`
+	StartAt   string   `json:"start_at"`
+	EndAt     string   `json:"end_at"`
+	Note      string   `json:"note"`
+	Tags      []string `json:"tags"`
+}
+
+// errorResult is how errors are printed in the json output format.
+type errorResult struct {
+	Error string `json:"error"`
+}
+
+// Results use fixed date and time formats, unlike view models which follow
+// the display settings, so scripts don't depend on the config.
+func newWorkDayResult(workDay model.WorkDay) workDayResult {
+	return workDayResult{Id: workDay.Id, Date: workDay.Date.Format(util.DateFormatStr)}
+}
+
+func newWorkPeriodResult(period model.WorkPeriod) workPeriodResult {
+	result := workPeriodResult{
+		Id:        period.Id,
+		WorkDayId: period.WorkDayId,
+		StartAt:   period.StartAt.Format(time.RFC3339),
+		Note:      period.Note.String,
+		Tags:      period.Tags,
+	}
+	if period.EndAt.Valid {
+		result.EndAt = period.EndAt.Time.Format(time.RFC3339)
+	}
+	if result.Tags == nil {
+		result.Tags = []string{}
+	}
+
+	return result
+}
+
+// render writes the view model with its template, or as JSON or TSV
+// depending on the output format.
+func render(out io.Writer, name string, vm any) error {
+	switch cfg.Output {
+	case "json":
+		return writeJSON(out, vm)
+	case "tsv":
+		return writeTSV(out, vm)
+	default:
+		return template.Render(out, name, vm)
+	}
+}
+
+// renderResult writes the result in the json and tsv output formats, or the
+// view model with its template otherwise.
+func renderResult(out io.Writer, name string, vm any, result any) error {
+	if cfg.Output == "json" || cfg.Output == "tsv" {
+		return render(out, "", result)
+	}
+
+	return render(out, name, vm)
+}
+
+// printResult prints the sentence describing a command's result, or the
+// result itself in the json and tsv output formats.
+func printResult(out io.Writer, result any, format string, args ...any) error {
+	if cfg.Output == "json" || cfg.Output == "tsv" {
+		return render(out, "", result)
+	}
+
+	_, err := fmt.Fprintf(out, format, args...)
+	return err
+}
+
+// printMessage prints a sentence, wrapped in a messageResult in the json and
+// tsv output formats.
+func printMessage(out io.Writer, format string, args ...any) error {
+	message := strings.TrimSpace(fmt.Sprintf(format, args...))
+	return printResult(out, messageResult{Message: message}, format, args...)
+}
+
+// fatal prints the error to stderr, as JSON in the json output format, and
+// exits.
+func fatal(err error) {
+	if resolvedOutput() == "json" {
+		json.NewEncoder(os.Stderr).Encode(errorResult{Error: err.Error()})
+		os.Exit(1)
+	}
+
+	log.Fatalln(err)
+}
+
+// resolvedOutput returns the output format even when the config hasn't been
+// loaded, such as when parsing flags fails.
+func resolvedOutput() string {
+	if outputFormat != "" {
+		return outputFormat
+	}
+
+	return cfg.Output
+}
+
+func writeJSON(out io.Writer, v any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeTSV writes a header row of JSON field names followed by the rows of
+// the field tagged `tsv:"rows"`, or by a single row of v's own fields when
+// there is no such field. Fields of embedded structs are flattened. Tabs,
+// newlines and backslashes in values are escaped with a backslash.
+func writeTSV(out io.Writer, v any) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+
+	rows := []reflect.Value{value}
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).Tag.Get("tsv") == "rows" {
+			rows = nil
+			for j := 0; j < value.Field(i).Len(); j++ {
+				rows = append(rows, value.Field(i).Index(j))
+			}
+
+			value = reflect.New(value.Field(i).Type().Elem()).Elem()
+			break
+		}
+	}
+
+	var header []string
+	var columns [][]int
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.Anonymous || name == "" || name == "-" || !isTSVValue(field.Type) {
+			continue
+		}
+
+		header = append(header, name)
+		columns = append(columns, field.Index)
+	}
+
+	lines := []string{strings.Join(header, "\t")}
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = formatTSVValue(row.FieldByIndex(column))
+		}
+
+		lines = append(lines, strings.Join(cells, "\t"))
+	}
+
+	_, err := fmt.Fprintln(out, strings.Join(lines, "\n"))
+	return err
+}
+
+// isTSVValue reports whether values of the type fit in a single TSV cell.
+func isTSVValue(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return false
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+
+	return true
+}
+
+func formatTSVValue(v reflect.Value) string {
+	var str string
+	if v.Kind() == reflect.Slice {
+		strs := make([]string, v.Len())
+		for i := range strs {
+			strs[i] = v.Index(i).String()
+		}
+
+		str = strings.Join(strs, ",")
+	} else {
+		str = fmt.Sprint(v.Interface())
+	}
+
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(str)
+}
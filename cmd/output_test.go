@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func setOutput(t *testing.T, output string) {
+	prevCfg := cfg
+	t.Cleanup(func() { cfg = prevCfg })
+	cfg.Output = output
+}
+
+// configureDisplay changes the display settings for the rest of the test.
+func configureDisplay(t *testing.T, settings util.Settings) {
+	t.Cleanup(func() { util.Configure(util.DefaultSettings()) })
+	util.Configure(settings)
+}
+
+func TestWriteTSV(t *testing.T) {
+	t.Run("rows", func(t *testing.T) {
+		vm := projectListViewModel{Projects: []projectViewModel{
+			{Id: 1, Name: "acme", Status: "active"},
+			{Id: 2, Name: "tabs\tand\nnewlines", Status: "archived"},
+		}}
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, writeTSV(out, vm))
+		testutil.AssertOutput(t, out, "id\tname\tstatus\n1\tacme\tactive\n2\ttabs\\tand\\nnewlines\tarchived\n")
+	})
+
+	t.Run("single row with embedded fields", func(t *testing.T) {
+		result := stopResult{
+			workPeriodResult: workPeriodResult{Id: 3, WorkDayId: 1, StartAt: "2023-09-01T09:00:00Z", Tags: []string{"a", "b"}},
+			Warnings:         []string{"too long"},
+		}
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, writeTSV(out, result))
+		testutil.AssertOutput(t, out, "id\twork_day_id\tstart_at\tend_at\tnote\ttags\twarnings\n3\t1\t2023-09-01T09:00:00Z\t\t\ta,b\ttoo long\n")
+	})
+}
+
+// failingWriter fails every write, like a closed pipe.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestPrintResultReturnsWriteErrors(t *testing.T) {
+	for _, output := range []string{"text", "json", "tsv"} {
+		t.Run(output, func(t *testing.T) {
+			setOutput(t, output)
+
+			err := runAddCmd(failingWriter{}, testutil.NewRepo(t), "2023-09-01", "", "")
+			if err == nil {
+				t.Error("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	t.Run("json result", func(t *testing.T) {
+		setOutput(t, "json")
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runAddCmd(out, repo, "2023-09-01", "", ""))
+		testutil.AssertOutput(t, out, `{
+  "id": 1,
+  "date": "2023-09-01"
+}
+`)
+	})
+
+	t.Run("json message", func(t *testing.T) {
+		setOutput(t, "json")
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runAddCmd(out, repo, "2023-09-01", "", ""))
+		testutil.AssertOutput(t, out, `{
+  "message": "Work day on 2023-09-01 already exists."
+}
+`)
+	})
+
+	wd, err := repo.GetWorkDayByDate(date)
+	testutil.AssertNoErr(t, err)
+	wp := model.NewWorkPeriod(wd)
+	wp.StartAt = date.Add(9 * time.Hour)
+	wp.SetEndAt(date.Add(12 * time.Hour))
+	wp.SetNote("Planning")
+	_, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	t.Run("json list result", func(t *testing.T) {
+		setOutput(t, "json")
+		configureDisplay(t, util.Settings{DateFormat: "02.01.2006", WeekStart: time.Monday})
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runListCmd(out, repo, listCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-01"}}))
+		testutil.AssertOutput(t, out, `{
+  "from": "2023-09-01",
+  "to": "2023-09-01",
+  "length_mins": 450,
+  "time_worked_mins": 180,
+  "balance_mins": -270,
+  "work_days": [
+    {
+      "id": 1,
+      "date": "2023-09-01",
+      "length_mins": 450,
+      "time_worked_mins": 180,
+      "balance_mins": -270,
+      "note": ""
+    }
+  ]
+}
+`)
+	})
+
+	t.Run("tsv report result", func(t *testing.T) {
+		setOutput(t, "tsv")
+		configureDisplay(t, util.Settings{DateFormat: "02.01.2006", WeekStart: time.Monday})
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runReportCmd(out, repo, reportCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-01"}, groupBy: "week"}))
+		testutil.AssertOutput(t, out, "start\tdays\texpected_mins\tworked_mins\tbalance_mins\n2023-08-28\t1\t450\t180\t-270\n")
+	})
+
+	t.Run("json balance result without adjustments", func(t *testing.T) {
+		setOutput(t, "json")
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runBalanceCmd(out, repo, ""))
+		testutil.AssertOutput(t, out, fmt.Sprintf(`{
+  "since": "",
+  "through": "%s",
+  "days": 1,
+  "expected_mins": 450,
+  "worked_mins": 180,
+  "adjustments_mins": 0,
+  "balance_mins": -270,
+  "balance_adjustments": []
+}
+`, util.TodayAtMidnight().AddDate(0, 0, -1).Format(util.DateFormatStr)))
+	})
+
+	t.Run("json check result", func(t *testing.T) {
+		setOutput(t, "json")
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runCheckCmd(out, repo, dateRangeArgs{fromStr: "2023-09-01", toStr: "2023-09-01"}))
+		testutil.AssertOutput(t, out, `{
+  "from": "2023-09-01",
+  "to": "2023-09-01",
+  "violations": []
+}
+`)
+	})
+
+	t.Run("json status without a work day", func(t *testing.T) {
+		setOutput(t, "json")
+
+		out := &bytes.Buffer{}
+		_, err := runStatusCmd(out, repo)
+		testutil.AssertNoErr(t, err)
+		if !bytes.Contains(out.Bytes(), []byte(`"tags": []`)) {
+			t.Errorf("Expected empty tags, got:\n%s", out)
+		}
+	})
+
+	b := model.NewBreak(wd)
+	b.StartAt = date.Add(10 * time.Hour)
+	b.SetEndAt(date.Add(10*time.Hour + 15*time.Minute))
+	_, err = repo.CreateBreak(b)
+	testutil.AssertNoErr(t, err)
+
+	startAt := date.Add(9 * time.Hour).Format(time.RFC3339)
+	endAt := date.Add(12 * time.Hour).Format(time.RFC3339)
+
+	t.Run("json show result", func(t *testing.T) {
+		setOutput(t, "json")
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runShowCmd(out, repo, "2023-09-01"))
+		testutil.AssertOutput(t, out, fmt.Sprintf(`{
+  "id": 1,
+  "date": "2023-09-01",
+  "length_mins": 450,
+  "time_worked_mins": 165,
+  "break_mins": 15,
+  "time_remaining_mins": 285,
+  "estimated_finish": "%s",
+  "note": "",
+  "work_periods": [
+    {
+      "id": 1,
+      "work_day_id": 1,
+      "start_at": "%s",
+      "end_at": "%s",
+      "note": "Planning",
+      "tags": [],
+      "time_worked_mins": 165,
+      "break_mins": 15
+    }
+  ],
+  "breaks": [
+    {
+      "id": 1,
+      "work_day_id": 1,
+      "start_at": "%s",
+      "end_at": "%s",
+      "duration_mins": 15,
+      "note": ""
+    }
+  ],
+  "violations": []
+}
+`, time.Now().Truncate(time.Minute).Add(285*time.Minute).Format(time.RFC3339), startAt, endAt, b.StartAt.Format(time.RFC3339), b.EndAt.Time.Format(time.RFC3339)))
+	})
+
+	t.Run("tsv show result", func(t *testing.T) {
+		setOutput(t, "tsv")
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runShowCmd(out, repo, "2023-09-01"))
+		testutil.AssertOutput(t, out, fmt.Sprintf("id\twork_day_id\tstart_at\tend_at\tnote\ttags\ttime_worked_mins\tbreak_mins\n1\t1\t%s\t%s\tPlanning\t\t165\t15\n", startAt, endAt))
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		setOutput(t, "json")
+
+		out := &bytes.Buffer{}
+		testutil.AssertNoErr(t, runProjectListCmd(out, repo, false))
+		testutil.AssertOutput(t, out, `{
+  "projects": []
+}
+`)
+	})
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause [time]",
+	Short: "Start an unpaid break during the ongoing work period",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		var timeStr string
+		if len(args) > 0 {
+			timeStr = args[0]
+		}
+
+		note := mustGetStringFlag(cmd, "note")
+		if err := runPauseCmd(os.Stdout, repo, timeStr, note); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var unpauseCmd = &cobra.Command{
+	Use:   "unpause [time]",
+	Short: "End the ongoing break",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		var timeStr string
+		if len(args) > 0 {
+			timeStr = args[0]
+		}
+
+		if err := runUnpauseCmd(os.Stdout, repo, timeStr); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	pauseCmd.Flags().StringP("note", "n", "", "break note")
+
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(unpauseCmd)
+}
+
+func runPauseCmd(out io.Writer, repo *repository.Repo, timeStr string, note string) error {
+	period, err := repo.GetOpenWorkPeriod()
+	if err != nil {
+		return fmt.Errorf("error loading open work period: %v", err)
+	}
+
+	if period.Id == 0 {
+		return printMessage(out, "Unable to find an ongoing work period.\n")
+	}
+
+	openBreak, err := repo.GetOpenBreak()
+	if err != nil {
+		return fmt.Errorf("error loading open break: %v", err)
+	}
+
+	if openBreak.Id != 0 {
+		return printMessage(out, "Already on a break since %s.\n", util.FormatDateTime(openBreak.StartAt))
+	}
+
+	startAt, err := util.ParseTimeString(timeStr)
+	if err != nil {
+		return fmt.Errorf("error parsing time string: %v", err)
+	}
+
+	if startAt.Before(period.StartAt) {
+		return fmt.Errorf("break must not start before the work period started at %s", util.FormatDateTime(period.StartAt))
+	}
+
+	b := model.Break{WorkDayId: period.WorkDayId, StartAt: startAt}
+	b.SetNote(note)
+
+	// Overlapping breaks would take the same time off twice.
+	breaks, err := repo.GetBreaks(model.WorkDay{Id: period.WorkDayId})
+	if err != nil {
+		return fmt.Errorf("error loading breaks: %v", err)
+	}
+
+	for _, other := range breaks {
+		if b.Overlaps(other) {
+			return fmt.Errorf("break must not overlap the break from %s to %s", util.FormatDateTime(other.StartAt), util.FormatDateTime(other.EndAt.Time))
+		}
+	}
+
+	b, err = repo.CreateBreak(b)
+	if err != nil {
+		return fmt.Errorf("error creating break: %v", err)
+	}
+
+	return printResult(out, newBreakResult(b), "Paused at %s.\n", util.FormatTime(startAt))
+}
+
+func runUnpauseCmd(out io.Writer, repo *repository.Repo, timeStr string) error {
+	b, err := repo.GetOpenBreak()
+	if err != nil {
+		return fmt.Errorf("error loading open break: %v", err)
+	}
+
+	if b.Id == 0 {
+		return printMessage(out, "Unable to find an ongoing break.\n")
+	}
+
+	endAt, err := util.ParseTimeString(timeStr)
+	if err != nil {
+		return fmt.Errorf("error parsing time string: %v", err)
+	}
+
+	b.SetEndAt(endAt)
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	b, err = repo.UpdateBreak(b)
+	if err != nil {
+		return fmt.Errorf("error updating break: %v", err)
+	}
+
+	return printResult(out, newBreakResult(b), "Unpaused after a %s break.\n", util.FormatDuration(b.Duration()))
+}
+
+// breakResult describes a break in the json and tsv output formats. Open
+// breaks have an empty end.
+type breakResult struct {
+	Id           int    `json:"id"`
+	WorkDayId    int    `json:"work_day_id"`
+	StartAt      string `json:"start_at"`
+	EndAt        string `json:"end_at"`
+	DurationMins int    `json:"duration_mins"`
+	Note         string `json:"note"`
+}
+
+func newBreakResult(b model.Break) breakResult {
+	result := breakResult{
+		Id:           b.Id,
+		WorkDayId:    b.WorkDayId,
+		StartAt:      b.StartAt.Format(time.RFC3339),
+		DurationMins: int(b.Duration().Minutes()),
+		Note:         b.Note.String,
+	}
+	if b.EndAt.Valid {
+		result.EndAt = b.EndAt.Time.Format(time.RFC3339)
+	}
+
+	return result
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunPauseCmdNoOpenPeriod(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runPauseCmd(out, repo, "", "")
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Unable to find an ongoing work period.\n")
+}
+
+func TestRunUnpauseCmdNoOpenBreak(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runUnpauseCmd(out, repo, "")
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Unable to find an ongoing break.\n")
+}
+
+func TestRunPauseAndUnpauseCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	period := model.NewWorkPeriod(workDay)
+	period.StartAt = time.Now().Add(-2 * time.Hour)
+	_, err = repo.CreateWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	pauseAt := time.Now().Add(-time.Hour)
+
+	t.Run("pause", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runPauseCmd(out, repo, "-1h", "Lunch")
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, fmt.Sprintf("Paused at %s.\n", util.FormatTime(pauseAt)))
+	})
+
+	t.Run("pause while paused", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runPauseCmd(out, repo, "", "")
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, fmt.Sprintf("Already on a break since %s.\n", util.FormatDateTime(pauseAt)))
+	})
+
+	t.Run("unpause", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runUnpauseCmd(out, repo, "-30m")
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "Unpaused after a 30m break.\n")
+
+		workDays, err := repo.GetWorkDaysInRange(workDay.Date, workDay.Date)
+		testutil.AssertNoErr(t, err)
+		if got := util.FormatDuration(workDays[0].TimeWorked()); got != "1h30m" {
+			t.Errorf("got %s worked, want 1h30m", got)
+		}
+	})
+}
+
+func TestRunPauseCmdBeforePeriodStart(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	_, err = repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
+	testutil.AssertNoErr(t, err)
+
+	if err := runPauseCmd(&bytes.Buffer{}, repo, "-1h", ""); err == nil {
+		t.Error("Expected an error but got none")
+	}
+}
+
+func TestRunPauseCmdOverlappingBreak(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	period := model.NewWorkPeriod(workDay)
+	period.StartAt = time.Now().Add(-2 * time.Hour)
+	_, err = repo.CreateWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	testutil.AssertNoErr(t, runPauseCmd(&bytes.Buffer{}, repo, "-1h", ""))
+	testutil.AssertNoErr(t, runUnpauseCmd(&bytes.Buffer{}, repo, "-30m"))
+
+	for _, timeStr := range []string{"-90m", "-50m"} {
+		t.Run(timeStr, func(t *testing.T) {
+			if err := runPauseCmd(&bytes.Buffer{}, repo, timeStr, ""); err == nil {
+				t.Error("Expected an error but got none")
+			}
+
+			breaks, err := repo.GetBreaks(workDay)
+			testutil.AssertNoErr(t, err)
+			if len(breaks) != 1 {
+				t.Errorf("got %d breaks, want 1", len(breaks))
+			}
+		})
+	}
+
+	t.Run("after the break", func(t *testing.T) {
+		testutil.AssertNoErr(t, runPauseCmd(&bytes.Buffer{}, repo, "-10m", ""))
+	})
+}
+
+func TestRunStopCmdEndsOpenBreak(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	period := model.NewWorkPeriod(workDay)
+	period.StartAt = time.Now().Add(-time.Hour)
+	_, err = repo.CreateWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	testutil.AssertNoErr(t, runPauseCmd(&bytes.Buffer{}, repo, "-30m", ""))
+	testutil.AssertNoErr(t, runStopCmd(&bytes.Buffer{}, repo, stopCmdArgs{}))
+
+	b, err := repo.GetOpenBreak()
+	testutil.AssertNoErr(t, err)
+	if b.Id != 0 {
+		t.Errorf("Expected the break to be ended, got %+v", b)
+	}
+}
+
+func TestRunStopCmdBeforeOpenBreak(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	period := model.NewWorkPeriod(workDay)
+	period.StartAt = time.Now().Add(-time.Hour)
+	_, err = repo.CreateWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	testutil.AssertNoErr(t, runPauseCmd(&bytes.Buffer{}, repo, "-30m", ""))
+
+	if err := runStopCmd(&bytes.Buffer{}, repo, stopCmdArgs{timeStr: "-45m"}); err == nil {
+		t.Fatal("Expected an error but got none")
+	}
+
+	open, err := repo.GetOpenWorkPeriod()
+	testutil.AssertNoErr(t, err)
+	if open.Id == 0 {
+		t.Error("Expected the work period to still be open")
+	}
+
+	b, err := repo.GetOpenBreak()
+	testutil.AssertNoErr(t, err)
+	if b.Id == 0 {
+		t.Error("Expected the break to still be open")
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/spf13/cobra"
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manages the projects work periods are attributed to",
+}
+
+var projectAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Adds a new project",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := runProjectAddCmd(os.Stdout, repo, args[0]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists projects",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		all := mustGetBoolFlag(cmd, "all")
+		if err := runProjectListCmd(os.Stdout, repo, all); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var projectArchiveCmd = &cobra.Command{
+	Use:   "archive <name>",
+	Short: "Archives a project so no new work periods can be attributed to it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := runProjectArchiveCmd(os.Stdout, repo, args[0]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	projectListCmd.Flags().BoolP("all", "a", false, "include archived projects")
+
+	projectCmd.AddCommand(projectAddCmd)
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectArchiveCmd)
+	rootCmd.AddCommand(projectCmd)
+}
+
+func runProjectAddCmd(out io.Writer, repo *repository.Repo, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("project name must not be empty")
+	}
+
+	existing, err := repo.GetProjectByName(name)
+	if err != nil {
+		return fmt.Errorf("error loading project: %v", err)
+	}
+
+	if existing.Id != 0 {
+		return printMessage(out, "Project '%s' already exists.\n", name)
+	}
+
+	project, err := repo.CreateProject(model.NewProject(name))
+	if err != nil {
+		return fmt.Errorf("error creating project: %v", err)
+	}
+
+	return printResult(out, newProjectViewModel(project), "Added project #%d (%s).\n", project.Id, project.Name)
+}
+
+func runProjectListCmd(out io.Writer, repo *repository.Repo, all bool) error {
+	projects, err := repo.GetProjects(all)
+	if err != nil {
+		return fmt.Errorf("error loading projects: %v", err)
+	}
+
+	if len(projects) == 0 {
+		return printResult(out, projectListViewModel{Projects: []projectViewModel{}}, "No projects yet.\n")
+	}
+
+	var vm projectListViewModel
+	for _, p := range projects {
+		vm.Projects = append(vm.Projects, newProjectViewModel(p))
+	}
+
+	return render(out, "project_list.txt", vm)
+}
+
+func runProjectArchiveCmd(out io.Writer, repo *repository.Repo, name string) error {
+	project, err := repo.GetProjectByName(name)
+	if err != nil {
+		return fmt.Errorf("error loading project: %v", err)
+	}
+
+	if project.Id == 0 {
+		return printMessage(out, "Unable to find project '%s'.\n", name)
+	}
+
+	if project.IsArchived() {
+		return printMessage(out, "Project '%s' is already archived.\n", name)
+	}
+
+	project.Archive()
+	project, err = repo.UpdateProject(project)
+	if err != nil {
+		return fmt.Errorf("error archiving project: %v", err)
+	}
+
+	return printResult(out, newProjectViewModel(project), "Archived project #%d (%s).\n", project.Id, project.Name)
+}
+
+type projectListViewModel struct {
+	Projects []projectViewModel `json:"projects" tsv:"rows"`
+}
+
+type projectViewModel struct {
+	Id     int    `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func newProjectViewModel(p model.Project) projectViewModel {
+	status := "active"
+	if p.IsArchived() {
+		status = "archived"
+	}
+	if p.Name == cfg.DefaultProject {
+		status += ", default"
+	}
+
+	return projectViewModel{Id: p.Id, Name: p.Name, Status: status}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunProjectAddCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	out := &bytes.Buffer{}
+	err := runProjectAddCmd(out, repo, "acme")
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Added project #1 (acme).\n")
+
+	out = &bytes.Buffer{}
+	err = runProjectAddCmd(out, repo, "acme")
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Project 'acme' already exists.\n")
+
+	if err := runProjectAddCmd(&bytes.Buffer{}, repo, " "); err == nil {
+		t.Error("Expected an error for an empty name but got none")
+	}
+}
+
+func TestRunProjectListCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	out := &bytes.Buffer{}
+	err := runProjectListCmd(out, repo, false)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "No projects yet.\n")
+
+	for _, name := range []string{"acme", "globex", "initech"} {
+		testutil.AssertNoErr(t, runProjectAddCmd(&bytes.Buffer{}, repo, name))
+	}
+	testutil.AssertNoErr(t, runProjectArchiveCmd(&bytes.Buffer{}, repo, "globex"))
+
+	prevCfg := cfg
+	cfg.DefaultProject = "acme"
+	t.Cleanup(func() { cfg = prevCfg })
+
+	t.Run("active only", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runProjectListCmd(out, repo, false)
+		testutil.AssertNoErr(t, err)
+
+		compareShowOutput(
+			t,
+			out.String(),
+			`
+ID	NAME                    	STATUS
+1	acme                    	active, default
+3	initech                 	active
+`,
+			map[string]string{},
+		)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runProjectListCmd(out, repo, true)
+		testutil.AssertNoErr(t, err)
+
+		compareShowOutput(
+			t,
+			out.String(),
+			`
+ID	NAME                    	STATUS
+1	acme                    	active, default
+2	globex                  	archived
+3	initech                 	active
+`,
+			map[string]string{},
+		)
+	})
+}
+
+func TestRunProjectArchiveCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	testutil.AssertNoErr(t, runProjectAddCmd(&bytes.Buffer{}, repo, "acme"))
+
+	testCases := []struct {
+		name       string
+		project    string
+		wantOutput string
+	}{
+		{name: "archive", project: "acme", wantOutput: "Archived project #1 (acme).\n"},
+		{name: "already archived", project: "acme", wantOutput: "Project 'acme' is already archived.\n"},
+		{name: "unknown", project: "globex", wantOutput: "Unable to find project 'globex'.\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := runProjectArchiveCmd(out, repo, tc.project)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertOutput(t, out, tc.wantOutput)
+		})
+	}
+}
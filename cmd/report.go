@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+type reportCmdArgs struct {
+	dateRange dateRangeArgs
+	groupBy   string
+	tags      []string
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarizes work hours by week or month",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := reportCmdArgs{
+			dateRange: mustGetDateRangeFlags(cmd),
+			groupBy:   mustGetStringFlag(cmd, "by"),
+			tags:      mustGetStringArrayFlag(cmd, "tag"),
+		}
+
+		if err := runReportCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	addDateRangeFlags(reportCmd)
+	reportCmd.Flags().StringP("by", "b", "week", "group work days by 'week' or 'month'")
+	reportCmd.Flags().StringArrayP("tag", "t", nil, "only count work periods with this tag, can be repeated")
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReportCmd(out io.Writer, repo *repository.Repo, args reportCmdArgs) error {
+	groupKey, err := reportGroupKeyFunc(args.groupBy)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := args.dateRange.resolve()
+	if err != nil {
+		return err
+	}
+
+	workDays, err := repo.GetWorkDaysInRange(from, to)
+	if err != nil {
+		return fmt.Errorf("error loading work days: %v", err)
+	}
+	workDays = filterWorkDaysByTags(workDays, args.tags)
+
+	if len(workDays) == 0 {
+		result := newReportResult(from, to, args.groupBy, nil, reportGroup{}, nil)
+		return printResult(out, result, "No work days between %s and %s.\n", util.FormatDate(from), util.FormatDate(to))
+	}
+
+	var groups []reportGroup
+	total := reportGroup{label: "TOTAL"}
+	for _, wd := range workDays {
+		start, label := groupKey(wd.Date)
+		if len(groups) == 0 || !groups[len(groups)-1].start.Equal(start) {
+			groups = append(groups, reportGroup{start: start, label: label})
+		}
+
+		groups[len(groups)-1].add(wd)
+		total.add(wd)
+	}
+
+	projects, err := reportProjects(repo, workDays)
+	if err != nil {
+		return err
+	}
+
+	vm := reportViewModel{
+		Title: fmt.Sprintf("%s to %s", util.FormatDate(from), util.FormatDate(to)),
+		Total: total.viewModel(),
+	}
+	for _, g := range groups {
+		vm.Groups = append(vm.Groups, g.viewModel())
+	}
+	for _, p := range projects {
+		vm.Projects = append(vm.Projects, p.viewModel())
+	}
+
+	result := newReportResult(from, to, args.groupBy, groups, total, projects)
+	return renderResult(out, "work_day_report.txt", vm, result)
+}
+
+// reportGroupKeyFunc returns a function giving the start of the week or
+// month a date falls in, along with its label.
+func reportGroupKeyFunc(groupBy string) (func(time.Time) (time.Time, string), error) {
+	switch groupBy {
+	case "week":
+		return func(date time.Time) (time.Time, string) {
+			start := util.StartOfWeek(date)
+			return start, "Week of " + util.FormatDate(start)
+		}, nil
+	case "month":
+		return func(date time.Time) (time.Time, string) {
+			return util.StartOfMonth(date), date.Format("January 2006")
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown grouping '%s', expected 'week' or 'month'", groupBy)
+	}
+}
+
+// reportProjects breaks the time worked down by project, with the time not
+// attributed to a project last. It returns nothing when no work period is
+// attributed to a project.
+func reportProjects(repo *repository.Repo, workDays []model.WorkDay) ([]reportProject, error) {
+	workedByProject := make(map[int64]time.Duration)
+	hasProjects := false
+	for _, wd := range workDays {
+		for _, wp := range wd.WorkPeriods() {
+			workedByProject[wp.ProjectId.Int64] += wp.TimeWorked()
+			hasProjects = hasProjects || wp.ProjectId.Valid
+		}
+	}
+
+	if !hasProjects {
+		return nil, nil
+	}
+
+	projects, err := repo.GetProjects(true)
+	if err != nil {
+		return nil, fmt.Errorf("error loading projects: %v", err)
+	}
+
+	var reportProjects []reportProject
+	for _, p := range projects {
+		if worked, ok := workedByProject[int64(p.Id)]; ok {
+			reportProjects = append(reportProjects, reportProject{id: p.Id, name: p.Name, worked: worked})
+		}
+	}
+
+	if worked, ok := workedByProject[0]; ok {
+		reportProjects = append(reportProjects, reportProject{worked: worked})
+	}
+
+	return reportProjects, nil
+}
+
+type reportGroup struct {
+	start    time.Time
+	label    string
+	days     int
+	expected time.Duration
+	worked   time.Duration
+}
+
+func (g *reportGroup) add(wd model.WorkDay) {
+	g.days++
+	g.expected += wd.Length()
+	g.worked += wd.TimeWorked()
+}
+
+func (g *reportGroup) viewModel() reportGroupViewModel {
+	return reportGroupViewModel{
+		Label:    g.label,
+		Days:     g.days,
+		Expected: util.FormatDuration(g.expected),
+		Worked:   util.FormatDuration(g.worked),
+		Balance:  util.FormatBalance(g.worked - g.expected),
+	}
+}
+
+func (g *reportGroup) hoursResult() reportHoursResult {
+	return reportHoursResult{
+		Days:         g.days,
+		ExpectedMins: int(g.expected.Minutes()),
+		WorkedMins:   int(g.worked.Minutes()),
+		BalanceMins:  int((g.worked - g.expected).Minutes()),
+	}
+}
+
+// reportProject is the time worked on a project. The time not attributed to
+// a project has no ID.
+type reportProject struct {
+	id     int
+	name   string
+	worked time.Duration
+}
+
+func (p reportProject) viewModel() reportProjectViewModel {
+	name := p.name
+	if p.id == 0 {
+		name = "(no project)"
+	}
+
+	return reportProjectViewModel{Name: name, Worked: util.FormatDuration(p.worked)}
+}
+
+type reportViewModel struct {
+	Title    string
+	Groups   []reportGroupViewModel
+	Total    reportGroupViewModel
+	Projects []reportProjectViewModel
+}
+
+type reportGroupViewModel struct {
+	Label    string
+	Days     int
+	Expected string
+	Worked   string
+	Balance  string
+}
+
+type reportProjectViewModel struct {
+	Name   string
+	Worked string
+}
+
+// reportResult is the report in the json and tsv output formats, with the
+// groups as the tsv rows. Groups start on the first day of their week or
+// month.
+type reportResult struct {
+	From     string                `json:"from"`
+	To       string                `json:"to"`
+	GroupBy  string                `json:"group_by"`
+	Groups   []reportGroupResult   `json:"groups" tsv:"rows"`
+	Total    reportHoursResult     `json:"total"`
+	Projects []reportProjectResult `json:"projects"`
+}
+
+type reportGroupResult struct {
+	Start string `json:"start"`
+	reportHoursResult
+}
+
+// reportHoursResult is the time worked against the time expected over the
+// days of a group or the whole report.
+type reportHoursResult struct {
+	Days         int `json:"days"`
+	ExpectedMins int `json:"expected_mins"`
+	WorkedMins   int `json:"worked_mins"`
+	BalanceMins  int `json:"balance_mins"`
+}
+
+// reportProjectResult is the time worked on a project, or with a project ID
+// of 0 and no name, the time not attributed to a project.
+type reportProjectResult struct {
+	ProjectId  int    `json:"project_id"`
+	Name       string `json:"name"`
+	WorkedMins int    `json:"worked_mins"`
+}
+
+func newReportResult(from time.Time, to time.Time, groupBy string, groups []reportGroup, total reportGroup, projects []reportProject) reportResult {
+	result := reportResult{
+		From:     from.Format(util.DateFormatStr),
+		To:       to.Format(util.DateFormatStr),
+		GroupBy:  groupBy,
+		Groups:   []reportGroupResult{},
+		Total:    total.hoursResult(),
+		Projects: []reportProjectResult{},
+	}
+
+	for _, g := range groups {
+		result.Groups = append(result.Groups, reportGroupResult{
+			Start:             g.start.Format(util.DateFormatStr),
+			reportHoursResult: g.hoursResult(),
+		})
+	}
+
+	for _, p := range projects {
+		result.Projects = append(result.Projects, reportProjectResult{ProjectId: p.id, Name: p.name, WorkedMins: int(p.worked.Minutes())})
+	}
+
+	return result
+}
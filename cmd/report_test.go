@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunReportCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	worked := map[time.Time]time.Duration{
+		time.Date(2023, 8, 31, 0, 0, 0, 0, time.Local): 8 * time.Hour,
+		time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local):  7 * time.Hour,
+		time.Date(2023, 9, 4, 0, 0, 0, 0, time.Local):  9 * time.Hour,
+	}
+	for date, duration := range worked {
+		wd, err := repo.CreateWorkDay(model.NewWorkDay(date))
+		testutil.AssertNoErr(t, err)
+
+		wp := model.NewWorkPeriod(wd)
+		wp.StartAt = date.Add(8 * time.Hour)
+		wp.SetEndAt(wp.StartAt.Add(duration))
+		_, err = repo.CreateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+	}
+
+	dateRange := dateRangeArgs{fromStr: "2023-08-01", toStr: "2023-09-30"}
+
+	t.Run("by week", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runReportCmd(out, repo, reportCmdArgs{dateRange: dateRange, groupBy: "week"})
+		testutil.AssertNoErr(t, err)
+
+		compareShowOutput(
+			t,
+			out.String(),
+			`
+2023-08-01 to 2023-09-30
+========================
+
+PERIOD			DAYS	EXPECTED	WORKED		BALANCE
+Week of 2023-08-28  	2	15h0m		15h0m		0m
+Week of 2023-09-04  	1	7h30m		9h0m		+1h30m
+
+TOTAL               	3	22h30m		24h0m		+1h30m
+`,
+			map[string]string{},
+		)
+	})
+
+	t.Run("by month", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runReportCmd(out, repo, reportCmdArgs{dateRange: dateRange, groupBy: "month"})
+		testutil.AssertNoErr(t, err)
+
+		compareShowOutput(
+			t,
+			out.String(),
+			`
+2023-08-01 to 2023-09-30
+========================
+
+PERIOD			DAYS	EXPECTED	WORKED		BALANCE
+August 2023         	1	7h30m		8h0m		+30m
+September 2023      	2	15h0m		16h0m		+1h0m
+
+TOTAL               	3	22h30m		24h0m		+1h30m
+`,
+			map[string]string{},
+		)
+	})
+
+	t.Run("unknown grouping", func(t *testing.T) {
+		err := runReportCmd(&bytes.Buffer{}, repo, reportCmdArgs{dateRange: dateRange, groupBy: "year"})
+		if err == nil {
+			t.Error("Expected an error but got none")
+		}
+	})
+}
+
+func TestRunReportCmdWithProjects(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 4, 0, 0, 0, 0, time.Local)
+
+	wd, err := repo.CreateWorkDay(model.NewWorkDay(date))
+	testutil.AssertNoErr(t, err)
+
+	acme, err := repo.CreateProject(model.NewProject("acme"))
+	testutil.AssertNoErr(t, err)
+
+	globex, err := repo.CreateProject(model.NewProject("globex"))
+	testutil.AssertNoErr(t, err)
+
+	periods := []struct {
+		start   int
+		end     int
+		project model.Project
+	}{
+		{start: 8, end: 11, project: acme},
+		{start: 11, end: 12},
+		{start: 13, end: 15, project: globex},
+		{start: 15, end: 16, project: acme},
+	}
+	for _, p := range periods {
+		wp := model.NewWorkPeriod(wd)
+		wp.StartAt = date.Add(time.Duration(p.start) * time.Hour)
+		wp.SetEndAt(date.Add(time.Duration(p.end) * time.Hour))
+		wp.SetProject(p.project)
+		_, err = repo.CreateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+	}
+
+	out := &bytes.Buffer{}
+	err = runReportCmd(out, repo, reportCmdArgs{dateRange: dateRangeArgs{fromStr: "2023-09-04", toStr: "2023-09-04"}, groupBy: "week"})
+	testutil.AssertNoErr(t, err)
+
+	compareShowOutput(
+		t,
+		out.String(),
+		`
+2023-09-04 to 2023-09-04
+========================
+
+PERIOD			DAYS	EXPECTED	WORKED		BALANCE
+Week of 2023-09-04  	1	7h30m		7h0m		-30m
+
+TOTAL               	1	7h30m		7h0m		-30m
+
+PROJECT			WORKED
+acme                	4h0m
+globex              	2h0m
+(no project)        	1h0m
+`,
+		map[string]string{},
+	)
+}
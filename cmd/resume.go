@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume [time]",
+	Short: "Start a new work period with the note, project and tags of the last one",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		var timeStr string
+		if len(args) > 0 {
+			timeStr = args[0]
+		}
+
+		if err := runResumeCmd(os.Stdout, repo, timeStr); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+}
+
+func runResumeCmd(out io.Writer, repo *repository.Repo, timeStr string) error {
+	startAt, err := util.ParseTimeString(timeStr)
+	if err != nil {
+		return fmt.Errorf("error parsing time string: %v", err)
+	}
+
+	last, err := repo.GetLastClosedWorkPeriod()
+	if err != nil {
+		return fmt.Errorf("error loading work period: %v", err)
+	}
+
+	if last.Id == 0 {
+		return printMessage(out, "No previous work period to resume.\n")
+	}
+
+	period := model.WorkPeriod{
+		StartAt:   startAt,
+		Note:      last.Note,
+		ProjectId: last.ProjectId,
+		Tags:      last.Tags,
+	}
+
+	return startWorkPeriod(out, repo, util.TodayAtMidnight(), period, "", "")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunResumeCmdNoPeriods(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runResumeCmd(out, repo, "")
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "No previous work period to resume.\n")
+}
+
+func TestRunResumeCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	project, err := repo.CreateProject(model.NewProject("acme"))
+	testutil.AssertNoErr(t, err)
+
+	yesterday := util.TodayAtMidnight().AddDate(0, 0, -1)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(yesterday))
+	testutil.AssertNoErr(t, err)
+
+	last := model.NewWorkPeriod(workDay)
+	last.StartAt = yesterday.Add(9 * time.Hour)
+	last.SetEndAt(yesterday.Add(12 * time.Hour))
+	last.SetNote("Billing API")
+	last.SetProject(project)
+	last.AddTags("review")
+	_, err = repo.CreateWorkPeriod(last)
+	testutil.AssertNoErr(t, err)
+
+	t.Run("starts a copy on today's work day", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runResumeCmd(out, repo, "")
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, fmt.Sprintf("Started tracking time on NEW work day #2 (%s).\n", util.FormatDate(util.TodayAtMidnight())))
+
+		got, err := repo.GetOpenWorkPeriod()
+		testutil.AssertNoErr(t, err)
+
+		testutil.AssertAroundTime(t, "start", got.StartAt, time.Now())
+		testutil.AssertEqualStructs(t, got.Note, last.Note)
+		testutil.AssertEqualStructs(t, got.ProjectId, last.ProjectId)
+		if !reflect.DeepEqual(got.Tags, last.Tags) {
+			t.Errorf("got tags %v, want %v", got.Tags, last.Tags)
+		}
+	})
+
+	t.Run("refuses with an open work period", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := runResumeCmd(out, repo, "")
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "This work day already has an open work period.\n")
+
+		periods, err := repo.GetWorkPeriods(model.WorkDay{Id: 2})
+		testutil.AssertNoErr(t, err)
+		if len(periods) != 1 {
+			t.Errorf("got %d work periods, want 1", len(periods))
+		}
+	})
+}
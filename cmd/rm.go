@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+type rmCmdArgs struct {
+	target string
+	yes    bool
+}
+
+var rmCmd = &cobra.Command{
+	Use:   "rm",
+	Short: "Deletes a work period or work day",
+}
+
+var rmPeriodCmd = &cobra.Command{
+	Use:   "period <id>",
+	Short: "Deletes a work period",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := rmCmdArgs{target: args[0], yes: mustGetBoolFlag(cmd, "yes")}
+		if err := runRmPeriodCmd(os.Stdin, os.Stdout, cmd.ErrOrStderr(), repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var rmDayCmd = &cobra.Command{
+	Use:   "day <date>",
+	Short: "Deletes a work day and all of its work periods",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := rmCmdArgs{target: args[0], yes: mustGetBoolFlag(cmd, "yes")}
+		if err := runRmDayCmd(os.Stdin, os.Stdout, cmd.ErrOrStderr(), repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rmPeriodCmd.Flags().BoolP("yes", "y", false, "delete without asking for confirmation")
+	rmDayCmd.Flags().BoolP("yes", "y", false, "delete without asking for confirmation")
+
+	rmCmd.AddCommand(rmPeriodCmd)
+	rmCmd.AddCommand(rmDayCmd)
+	rootCmd.AddCommand(rmCmd)
+}
+
+func runRmPeriodCmd(in io.Reader, out io.Writer, promptOut io.Writer, repo *repository.Repo, args rmCmdArgs) error {
+	id, err := strconv.Atoi(args.target)
+	if err != nil {
+		return fmt.Errorf("invalid work period ID '%s'", args.target)
+	}
+
+	period, err := repo.GetWorkPeriodById(id)
+	if err != nil {
+		return fmt.Errorf("error loading work period: %v", err)
+	}
+
+	if period.Id == 0 {
+		return printMessage(out, "Unable to find work period #%d.\n", id)
+	}
+
+	question := fmt.Sprintf("Delete work period #%d started at %s?", period.Id, util.FormatDateTime(period.StartAt))
+	if !args.yes && !confirm(in, promptOut, question) {
+		return printMessage(out, "Aborted.\n")
+	}
+
+	if err := repo.DeleteWorkPeriod(period); err != nil {
+		return fmt.Errorf("error deleting work period: %v", err)
+	}
+
+	return printResult(out, newWorkPeriodResult(period), "Deleted work period #%d.\n", period.Id)
+}
+
+func runRmDayCmd(in io.Reader, out io.Writer, promptOut io.Writer, repo *repository.Repo, args rmCmdArgs) error {
+	date, err := util.ParseDateString(args.target)
+	if err != nil {
+		return fmt.Errorf("error parsing date: %v", err)
+	}
+
+	workDay, err := repo.GetWorkDayByDate(date)
+	if err != nil {
+		return fmt.Errorf("error loading work day: %v", err)
+	}
+
+	if workDay.Id == 0 {
+		return printMessage(out, "No work day for %s yet.\n", util.FormatDate(date))
+	}
+
+	periods, err := repo.GetWorkPeriods(workDay)
+	if err != nil {
+		return fmt.Errorf("error loading work periods: %v", err)
+	}
+
+	question := fmt.Sprintf("Delete work day #%d (%s) and its %d work period(s)?", workDay.Id, util.FormatDate(workDay.Date), len(periods))
+	if !args.yes && !confirm(in, promptOut, question) {
+		return printMessage(out, "Aborted.\n")
+	}
+
+	if err := repo.DeleteWorkDay(workDay); err != nil {
+		return fmt.Errorf("error deleting work day: %v", err)
+	}
+
+	return printResult(out, newWorkDayResult(workDay), "Deleted work day #%d (%s).\n", workDay.Id, util.FormatDate(workDay.Date))
+}
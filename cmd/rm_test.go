@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunRmPeriodCmd(t *testing.T) {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name       string
+		input      string
+		yes        bool
+		wantOutput string
+		wantCount  int
+	}{
+		{
+			name:       "confirmed",
+			input:      "y\n",
+			wantOutput: "Delete work period #1 started at 2023-09-01 9:00 AM? [y/N] Deleted work period #1.\n",
+			wantCount:  0,
+		},
+		{
+			name:       "declined",
+			input:      "\n",
+			wantOutput: "Delete work period #1 started at 2023-09-01 9:00 AM? [y/N] Aborted.\n",
+			wantCount:  1,
+		},
+		{
+			name:       "yes flag",
+			yes:        true,
+			wantOutput: "Deleted work period #1.\n",
+			wantCount:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := testutil.NewRepo(t)
+			workDay, err := repo.CreateWorkDay(model.NewWorkDay(date))
+			testutil.AssertNoErr(t, err)
+
+			period := model.NewWorkPeriod(workDay)
+			period.StartAt = date.Add(9 * time.Hour)
+			_, err = repo.CreateWorkPeriod(period)
+			testutil.AssertNoErr(t, err)
+
+			out := &bytes.Buffer{}
+			err = runRmPeriodCmd(strings.NewReader(tc.input), out, out, repo, rmCmdArgs{target: "1", yes: tc.yes})
+			testutil.AssertNoErr(t, err)
+			testutil.AssertOutput(t, out, tc.wantOutput)
+
+			periods, err := repo.GetWorkPeriods(workDay)
+			testutil.AssertNoErr(t, err)
+
+			if len(periods) != tc.wantCount {
+				t.Errorf("got %d work periods, want %d", len(periods), tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestRunRmDayCmd(t *testing.T) {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name       string
+		input      string
+		yes        bool
+		wantOutput string
+		wantCount  int
+	}{
+		{
+			name:       "confirmed",
+			input:      "yes\n",
+			wantOutput: "Delete work day #1 (2023-09-01) and its 2 work period(s)? [y/N] Deleted work day #1 (2023-09-01).\n",
+			wantCount:  0,
+		},
+		{
+			name:       "declined",
+			input:      "n\n",
+			wantOutput: "Delete work day #1 (2023-09-01) and its 2 work period(s)? [y/N] Aborted.\n",
+			wantCount:  1,
+		},
+		{
+			name:       "yes flag",
+			yes:        true,
+			wantOutput: "Deleted work day #1 (2023-09-01).\n",
+			wantCount:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := testutil.NewRepo(t)
+			workDay, err := repo.CreateWorkDay(model.NewWorkDay(date))
+			testutil.AssertNoErr(t, err)
+
+			for i := 0; i < 2; i++ {
+				_, err = repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
+				testutil.AssertNoErr(t, err)
+			}
+
+			out := &bytes.Buffer{}
+			err = runRmDayCmd(strings.NewReader(tc.input), out, out, repo, rmCmdArgs{target: "2023-09-01", yes: tc.yes})
+			testutil.AssertNoErr(t, err)
+			testutil.AssertOutput(t, out, tc.wantOutput)
+
+			gotCount, err := repo.GetWorkDayCount()
+			testutil.AssertNoErr(t, err)
+
+			if gotCount != tc.wantCount {
+				t.Errorf("got %d work days, want %d", gotCount, tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestRunRmPeriodCmdPromptOutput(t *testing.T) {
+	setOutput(t, "json")
+
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)))
+	testutil.AssertNoErr(t, err)
+	_, err = repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
+	testutil.AssertNoErr(t, err)
+
+	out, promptOut := &bytes.Buffer{}, &bytes.Buffer{}
+	err = runRmPeriodCmd(strings.NewReader("n\n"), out, promptOut, repo, rmCmdArgs{target: "1"})
+	testutil.AssertNoErr(t, err)
+
+	testutil.AssertOutput(t, out, "{\n  \"message\": \"Aborted.\"\n}\n")
+	if !strings.HasPrefix(promptOut.String(), "Delete work period #1") {
+		t.Errorf("Expected the question on the prompt output, got '%s'", promptOut.String())
+	}
+}
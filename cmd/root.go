@@ -1,15 +1,36 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/robyparr/wh/config"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// databasePathEnvVar overrides the default database location when the --db
+// flag isn't given.
+const databasePathEnvVar string = "WH_DB"
+
+var cfgFile string
+var dbPath string
+var cfg = config.Default()
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "wh",
 	Short: "A simple CLI tool to track work hours.",
+	// Errors are printed by Execute so they can follow the output format.
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return loadConfig()
+	},
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
@@ -18,8 +39,23 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	// Usage doesn't belong in machine-readable output, and the output flag isn't
+	// parsed at all when the command isn't found, so look for it up front.
+	flags := pflag.NewFlagSet("output", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	flags.StringVarP(&outputFormat, "output", "o", "", "")
+	flags.Parse(os.Args[1:])
+	rootCmd.SilenceUsage = outputFormat == "json" || outputFormat == "tsv"
+
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
+		if resolvedOutput() == "json" {
+			fatal(err)
+		}
+
+		cmd.PrintErrln("Error:", err.Error())
+		cmd.PrintErrf("Run '%v --help' for usage.\n", cmd.CommandPath())
 		os.Exit(1)
 	}
 }
@@ -29,9 +65,134 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.wh.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/wh/config.toml)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", fmt.Sprintf("output format (%s, default is the config's output or text)", strings.Join(config.OutputFormats, ", ")))
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "", fmt.Sprintf("database file (default is $XDG_DATA_HOME/wh/wh.sqlite, or $%s)", databasePathEnvVar))
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// loadConfig reads the config file given by --config, or the default one if
+// it exists, and applies its formatting settings. The --output flag overrides
+// the config's output format.
+func loadConfig() error {
+	path, mustExist := cfgFile, true
+	if path == "" {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			return fmt.Errorf("error finding default config path: %v", err)
+		}
+
+		path, mustExist = defaultPath, false
+	}
+
+	loaded, err := config.Load(path, mustExist)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat != "" {
+		loaded.Output = outputFormat
+		if err := loaded.Validate(); err != nil {
+			return err
+		}
+	}
+
+	cfg = loaded
+	util.Configure(cfg.UtilSettings())
+	return nil
+}
+
+// openRepo opens the database given by the --db flag, the WH_DB environment
+// variable or the default location, in that order of precedence, and applies
+// any pending migrations.
+func openRepo() (*repository.Repo, error) {
+	path, err := prepareDatabasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.NewRepo(path)
+}
+
+// openUnmigratedRepo opens the same database as openRepo without migrating
+// it, for the commands that manage migrations themselves.
+func openUnmigratedRepo() (*repository.Repo, error) {
+	path, err := prepareDatabasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Open(path)
+}
+
+// prepareDatabasePath resolves the database path and creates its directory.
+func prepareDatabasePath() (string, error) {
+	path, isDefault, err := resolveDatabasePath()
+	if err != nil {
+		return "", err
+	}
+
+	if isDefault {
+		if err := checkLegacyDatabase(path); err != nil {
+			return "", err
+		}
+	}
+
+	return path, createDatabaseDir(path)
+}
+
+// resolveDatabasePath returns the database file to use and whether it's the
+// default location rather than one given by --db or the environment.
+func resolveDatabasePath() (string, bool, error) {
+	if dbPath != "" {
+		return dbPath, false, nil
+	}
+
+	if path := os.Getenv(databasePathEnvVar); path != "" {
+		return path, false, nil
+	}
+
+	path, err := repository.DefaultDatabasePath()
+	if err != nil {
+		return "", false, fmt.Errorf("error finding default database path: %v", err)
+	}
+
+	return path, true, nil
+}
+
+// checkLegacyDatabase refuses to create a database at the default path while
+// the working directory has a database from an older version of wh, so that
+// a new, empty database doesn't hide the user's history. Once the default
+// database exists, the legacy one is ignored.
+func checkLegacyDatabase(path string) error {
+	if fileExists(path) || !repository.IsDatabase(repository.LegacyDatabasePath) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"found a database at %s from an older version of wh, which is no longer used by default.\n"+
+			"To keep your history, move it to the new location:\n\n\tmkdir -p %s && mv %s %s\n\n"+
+			"point wh at it with --db or $%s, or start a new, empty database with 'wh db init'",
+		repository.LegacyDatabasePath,
+		filepath.Dir(path),
+		repository.LegacyDatabasePath,
+		path,
+		databasePathEnvVar,
+	)
+}
+
+func createDatabaseDir(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating database directory: %v", err)
+	}
+
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/config"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestResolveDatabasePath(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv(databasePathEnvVar, "")
+	defaultPath := filepath.Join(dataHome, "wh", "wh.sqlite")
+
+	testCases := []struct {
+		name   string
+		flag   string
+		envVar string
+		want   string
+	}{
+		{name: "default", want: defaultPath},
+		{name: "env var", envVar: "/tmp/env.sqlite", want: "/tmp/env.sqlite"},
+		{name: "flag", flag: "/tmp/flag.sqlite", want: "/tmp/flag.sqlite"},
+		{name: "flag over env var", flag: "/tmp/flag.sqlite", envVar: "/tmp/env.sqlite", want: "/tmp/flag.sqlite"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbPath = tc.flag
+			t.Cleanup(func() { dbPath = "" })
+			t.Setenv(databasePathEnvVar, tc.envVar)
+
+			got, isDefault, err := resolveDatabasePath()
+			testutil.AssertNoErr(t, err)
+
+			if isDefault != (tc.want == defaultPath) {
+				t.Errorf("got default %v for '%s'", isDefault, got)
+			}
+
+			if got != tc.want {
+				t.Errorf("got '%s', want '%s'", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveDatabasePathLegacyHint(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv(databasePathEnvVar, "")
+
+	workDir := t.TempDir()
+	prevWorkDir, err := os.Getwd()
+	testutil.AssertNoErr(t, err)
+	testutil.AssertNoErr(t, os.Chdir(workDir))
+	t.Cleanup(func() { os.Chdir(prevWorkDir) })
+
+	defaultPath := filepath.Join(dataHome, "wh", "wh.sqlite")
+	legacyPath := filepath.Join(workDir, "db.sqlite")
+
+	t.Run("no legacy database", func(t *testing.T) {
+		_, err := prepareDatabasePath()
+		testutil.AssertNoErr(t, err)
+	})
+
+	t.Run("other file named like the legacy database", func(t *testing.T) {
+		testutil.AssertNoErr(t, os.WriteFile(legacyPath, nil, 0o644))
+		t.Cleanup(func() { os.Remove(legacyPath) })
+
+		_, err := prepareDatabasePath()
+		testutil.AssertNoErr(t, err)
+	})
+
+	_, err = repository.NewRepo(legacyPath)
+	testutil.AssertNoErr(t, err)
+
+	t.Run("legacy database", func(t *testing.T) {
+		_, err := openRepo()
+		if err == nil {
+			t.Fatal("Expected an error but got none")
+		}
+
+		if !strings.Contains(err.Error(), "mv ./db.sqlite "+defaultPath) {
+			t.Errorf("Expected a migration hint, got '%s'", err.Error())
+		}
+
+		if !strings.Contains(err.Error(), "wh db init") {
+			t.Errorf("Expected a way to start a new database, got '%s'", err.Error())
+		}
+
+		if fileExists(defaultPath) {
+			t.Error("Expected no database to be created at the default path")
+		}
+	})
+
+	t.Run("legacy database after db init", func(t *testing.T) {
+		testutil.AssertNoErr(t, runDbInitCmd(&bytes.Buffer{}, defaultPath))
+
+		got, err := prepareDatabasePath()
+		testutil.AssertNoErr(t, err)
+
+		if got != defaultPath {
+			t.Errorf("got '%s', want '%s'", got, defaultPath)
+		}
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Cleanup(func() {
+		cfgFile = ""
+		cfg = config.Default()
+		util.Configure(util.DefaultSettings())
+	})
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	t.Run("no config file", func(t *testing.T) {
+		testutil.AssertNoErr(t, loadConfig())
+		testutil.AssertEqualStructs(t, cfg, config.Default())
+	})
+
+	t.Run("config flag", func(t *testing.T) {
+		cfgFile = filepath.Join(t.TempDir(), "wh.toml")
+		testutil.AssertNoErr(t, os.WriteFile(cfgFile, []byte(`time_format = "24h"`), 0o644))
+
+		testutil.AssertNoErr(t, loadConfig())
+
+		if cfg.TimeFormat != "24h" {
+			t.Errorf("got time format '%s', want '24h'", cfg.TimeFormat)
+		}
+
+		date := time.Date(2023, 9, 1, 17, 30, 0, 0, time.Local)
+		if got := util.FormatDateTime(date); got != "2023-09-01 17:30" {
+			t.Errorf("got '%s', want '2023-09-01 17:30'", got)
+		}
+	})
+
+	t.Run("missing config flag file", func(t *testing.T) {
+		cfgFile = filepath.Join(t.TempDir(), "missing.toml")
+		if err := loadConfig(); err == nil {
+			t.Error("Expected an error but got none")
+		}
+	})
+}
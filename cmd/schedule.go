@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+// scheduleWeekdays lists the weekdays in the order they're displayed.
+var scheduleWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+type scheduleSetCmdArgs struct {
+	fromStr    string
+	lengthStrs map[time.Weekday]string
+}
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Lists the work schedules used for new work day lengths",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := runScheduleCmd(os.Stdout, repo); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var scheduleSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Sets the work day length for each weekday from a date on",
+	Long: `Sets the work day length for each weekday from a date on.
+
+Weekdays without a flag default to the configured day length from Monday to
+Friday and to nothing on weekends. Existing work days keep their length.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		cmdArgs := scheduleSetCmdArgs{
+			fromStr:    mustGetStringFlag(cmd, "from"),
+			lengthStrs: make(map[time.Weekday]string),
+		}
+		for _, day := range scheduleWeekdays {
+			if lengthStr := mustGetStringFlag(cmd, weekdayFlagName(day)); lengthStr != "" {
+				cmdArgs.lengthStrs[day] = lengthStr
+			}
+		}
+
+		if err := runScheduleSetCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	scheduleSetCmd.Flags().StringP("from", "f", "", "date the schedule takes effect (default today)")
+	for _, day := range scheduleWeekdays {
+		scheduleSetCmd.Flags().String(weekdayFlagName(day), "", fmt.Sprintf("work day length on %ss (e.g. 8h)", day))
+	}
+
+	scheduleCmd.AddCommand(scheduleSetCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+func weekdayFlagName(day time.Weekday) string {
+	return strings.ToLower(day.String()[:3])
+}
+
+func runScheduleCmd(out io.Writer, repo *repository.Repo) error {
+	schedules, err := repo.GetSchedules()
+	if err != nil {
+		return fmt.Errorf("error loading schedules: %v", err)
+	}
+
+	result := scheduleListResult{Schedules: []scheduleResult{}}
+	for _, s := range schedules {
+		result.Schedules = append(result.Schedules, newScheduleResult(s))
+	}
+
+	if len(schedules) == 0 {
+		return printResult(out, result, "No schedules yet; work days are %s long.\n", util.FormatDuration(time.Duration(cfg.DayLength)))
+	}
+
+	var vm scheduleListViewModel
+	for _, s := range schedules {
+		vm.Schedules = append(vm.Schedules, newScheduleViewModel(s))
+	}
+
+	return renderResult(out, "schedule_list.txt", vm, result)
+}
+
+func runScheduleSetCmd(out io.Writer, repo *repository.Repo, args scheduleSetCmdArgs) error {
+	from := util.TodayAtMidnight()
+	if args.fromStr != "" {
+		date, err := util.ParseDateString(args.fromStr)
+		if err != nil {
+			return fmt.Errorf("error parsing from date: %v", err)
+		}
+
+		from = date
+	}
+
+	schedule := model.NewSchedule(from, time.Duration(cfg.DayLength))
+	for day, lengthStr := range args.lengthStrs {
+		length, err := time.ParseDuration(lengthStr)
+		if err != nil {
+			return fmt.Errorf("error parsing %s length: %v", day, err)
+		}
+
+		if length < 0 {
+			return fmt.Errorf("%s length must not be negative", day)
+		}
+
+		schedule.SetLength(day, length)
+	}
+
+	schedule, err := repo.CreateSchedule(schedule)
+	if err != nil {
+		return fmt.Errorf("error creating schedule: %v", err)
+	}
+
+	return printResult(out, newScheduleResult(schedule), "Set schedule #%d effective from %s.\n", schedule.Id, util.FormatDate(schedule.EffectiveFrom))
+}
+
+type scheduleListViewModel struct {
+	Schedules []scheduleViewModel
+}
+
+type scheduleViewModel struct {
+	Id            int
+	EffectiveFrom string
+	Lengths       []string
+}
+
+// newScheduleViewModel lists the schedule's lengths from Monday to Sunday.
+func newScheduleViewModel(s model.Schedule) scheduleViewModel {
+	vm := scheduleViewModel{Id: s.Id, EffectiveFrom: util.FormatDate(s.EffectiveFrom)}
+	for _, day := range scheduleWeekdays {
+		vm.Lengths = append(vm.Lengths, util.FormatDuration(s.Length(day)))
+	}
+
+	return vm
+}
+
+// scheduleListResult is the schedules in the json and tsv output formats,
+// with the schedules as the tsv rows.
+type scheduleListResult struct {
+	Schedules []scheduleResult `json:"schedules" tsv:"rows"`
+}
+
+type scheduleResult struct {
+	Id            int    `json:"id"`
+	EffectiveFrom string `json:"effective_from"`
+	MondayMins    int    `json:"monday_mins"`
+	TuesdayMins   int    `json:"tuesday_mins"`
+	WednesdayMins int    `json:"wednesday_mins"`
+	ThursdayMins  int    `json:"thursday_mins"`
+	FridayMins    int    `json:"friday_mins"`
+	SaturdayMins  int    `json:"saturday_mins"`
+	SundayMins    int    `json:"sunday_mins"`
+}
+
+func newScheduleResult(s model.Schedule) scheduleResult {
+	mins := func(day time.Weekday) int { return int(s.Length(day).Minutes()) }
+	return scheduleResult{
+		Id:            s.Id,
+		EffectiveFrom: s.EffectiveFrom.Format(util.DateFormatStr),
+		MondayMins:    mins(time.Monday),
+		TuesdayMins:   mins(time.Tuesday),
+		WednesdayMins: mins(time.Wednesday),
+		ThursdayMins:  mins(time.Thursday),
+		FridayMins:    mins(time.Friday),
+		SaturdayMins:  mins(time.Saturday),
+		SundayMins:    mins(time.Sunday),
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunScheduleCmdNoSchedules(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runScheduleCmd(out, repo)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "No schedules yet; work days are 7h30m long.\n")
+}
+
+func TestRunScheduleSetCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	out := &bytes.Buffer{}
+	err := runScheduleSetCmd(out, repo, scheduleSetCmdArgs{
+		fromStr:    "2023-01-01",
+		lengthStrs: map[time.Weekday]string{time.Monday: "8h", time.Friday: "4h"},
+	})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Set schedule #1 effective from 2023-01-01.\n")
+
+	err = runScheduleSetCmd(&bytes.Buffer{}, repo, scheduleSetCmdArgs{
+		fromStr:    "2023-09-01",
+		lengthStrs: map[time.Weekday]string{time.Sunday: "1h"},
+	})
+	testutil.AssertNoErr(t, err)
+
+	out = &bytes.Buffer{}
+	err = runScheduleCmd(out, repo)
+	testutil.AssertNoErr(t, err)
+
+	compareShowOutput(
+		t,
+		out.String(),
+		`
+EFFECTIVE FROM	MON	TUE	WED	THU	FRI	SAT	SUN
+2023-01-01     	8h0m	7h30m	7h30m	7h30m	4h0m	0m	0m
+2023-09-01     	7h30m	7h30m	7h30m	7h30m	7h30m	0m	1h0m
+`,
+		map[string]string{},
+	)
+
+	t.Run("invalid length", func(t *testing.T) {
+		err := runScheduleSetCmd(&bytes.Buffer{}, repo, scheduleSetCmdArgs{
+			lengthStrs: map[time.Weekday]string{time.Monday: "-1h"},
+		})
+		if err == nil {
+			t.Error("Expected an error but got none")
+		}
+	})
+}
@@ -3,11 +3,12 @@ package cmd
 import (
 	"fmt"
 	"io"
-	"log"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/robyparr/wh/model"
 	"github.com/robyparr/wh/repository"
-	"github.com/robyparr/wh/template"
 	"github.com/robyparr/wh/util"
 	"github.com/spf13/cobra"
 )
@@ -16,9 +17,9 @@ var showCmd = &cobra.Command{
 	Use:   "show [date]",
 	Short: "Shows details about a work day",
 	Run: func(cmd *cobra.Command, args []string) {
-		repo, err := repository.NewRepo(repository.DefaultDatabasePath)
+		repo, err := openRepo()
 		if err != nil {
-			log.Fatalln(err)
+			fatal(err)
 		}
 
 		var dateStr string
@@ -26,7 +27,7 @@ var showCmd = &cobra.Command{
 			dateStr = args[0]
 		}
 		if err := runShowCmd(os.Stdout, repo, dateStr); err != nil {
-			log.Fatalln(err)
+			fatal(err)
 		}
 	},
 }
@@ -47,8 +48,7 @@ func runShowCmd(out io.Writer, repo *repository.Repo, dateStr string) error {
 	}
 
 	if workDay.Id == 0 {
-		fmt.Fprintf(out, "No work day for %s yet.\n", dateStr)
-		return nil
+		return printMessage(out, "No work day for %s yet.\n", dateStr)
 	}
 
 	workPeriods, err := repo.GetWorkPeriods(workDay)
@@ -57,16 +57,24 @@ func runShowCmd(out io.Writer, repo *repository.Repo, dateStr string) error {
 	}
 
 	workDay.SetWorkPeriods(workPeriods)
+
+	breaks, err := repo.GetBreaks(workDay)
+	if err != nil {
+		return fmt.Errorf("error loading breaks: %v", err)
+	}
+	workDay.SetBreaks(breaks)
+
 	vm := showViewModel{
-		Title:           util.Underline(workDay.Date.Format("January 02, 2006 (Mon)")),
+		Title:           workDay.Date.Format("January 02, 2006 (Mon)"),
 		DayLength:       util.FormatDuration(workDay.Length()),
 		TimeWorked:      util.FormatDuration(workDay.TimeWorked()),
+		BreakTime:       util.FormatDuration(workDay.BreakTime()),
 		TimeRemaining:   util.FormatDuration(workDay.TimeRemaining()),
 		EstimatedFinish: util.FormatDateTime(workDay.EstimatedFinish()),
 		Note:            workDay.Note.String,
 	}
 	for _, wp := range workPeriods {
-		endAt := "-"
+		var endAt string
 		if !wp.EndAt.Time.IsZero() {
 			endAt = util.FormatDateTime(wp.EndAt.Time)
 		}
@@ -74,27 +82,105 @@ func runShowCmd(out io.Writer, repo *repository.Repo, dateStr string) error {
 		vm.WorkPeriods = append(vm.WorkPeriods, showPeriodViewModel{
 			Id:         wp.Id,
 			StartAt:    util.FormatDateTime(wp.StartAt),
-			EndAt:      fmt.Sprintf("%-20s", endAt),
+			EndAt:      endAt,
 			TimeWorked: util.FormatDuration(wp.TimeWorked()),
+			Tags:       formatTags(wp.Tags),
 			Note:       wp.Note.String,
 		})
 	}
 
-	if err := template.Render(out, "work_day_show.txt", vm); err != nil {
+	for _, b := range breaks {
+		var endAt string
+		if b.EndAt.Valid {
+			endAt = util.FormatDateTime(b.EndAt.Time)
+		}
+
+		vm.Breaks = append(vm.Breaks, showBreakViewModel{
+			Id:       b.Id,
+			StartAt:  util.FormatDateTime(b.StartAt),
+			EndAt:    endAt,
+			Duration: util.FormatDuration(b.Duration()),
+			Note:     b.Note.String,
+		})
+	}
+
+	violations, err := checkCompliance(repo, workDay.Date, workDay.Date)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	for _, v := range violations {
+		vm.Violations = append(vm.Violations, v.Message)
+	}
+
+	return renderResult(out, "work_day_show.txt", vm, newShowResult(workDay, vm.Violations))
+}
+
+// showResult is the work day shown by show in the json and tsv output
+// formats, with its work periods as the tsv rows.
+type showResult struct {
+	workDayResult
+	LengthMins        int                `json:"length_mins"`
+	TimeWorkedMins    int                `json:"time_worked_mins"`
+	BreakMins         int                `json:"break_mins"`
+	TimeRemainingMins int                `json:"time_remaining_mins"`
+	EstimatedFinish   string             `json:"estimated_finish"`
+	Note              string             `json:"note"`
+	WorkPeriods       []showPeriodResult `json:"work_periods" tsv:"rows"`
+	Breaks            []breakResult      `json:"breaks"`
+	Violations        []string           `json:"violations"`
+}
+
+// showPeriodResult is a work period with the time worked during it, which
+// leaves out its breaks.
+type showPeriodResult struct {
+	workPeriodResult
+	TimeWorkedMins int `json:"time_worked_mins"`
+	BreakMins      int `json:"break_mins"`
+}
+
+func newShowResult(workDay model.WorkDay, violations []string) showResult {
+	result := showResult{
+		workDayResult:     newWorkDayResult(workDay),
+		LengthMins:        workDay.LengthMins,
+		TimeWorkedMins:    int(workDay.TimeWorked().Minutes()),
+		BreakMins:         int(workDay.BreakTime().Minutes()),
+		TimeRemainingMins: int(workDay.TimeRemaining().Minutes()),
+		EstimatedFinish:   workDay.EstimatedFinish().Format(time.RFC3339),
+		Note:              workDay.Note.String,
+		WorkPeriods:       []showPeriodResult{},
+		Breaks:            []breakResult{},
+		Violations:        []string{},
+	}
+
+	for _, wp := range workDay.WorkPeriods() {
+		breakTime := workDay.BreakTimeDuring(wp)
+		result.WorkPeriods = append(result.WorkPeriods, showPeriodResult{
+			workPeriodResult: newWorkPeriodResult(wp),
+			TimeWorkedMins:   int((wp.TimeWorked() - breakTime).Minutes()),
+			BreakMins:        int(breakTime.Minutes()),
+		})
+	}
+
+	for _, b := range workDay.Breaks() {
+		result.Breaks = append(result.Breaks, newBreakResult(b))
+	}
+
+	result.Violations = append(result.Violations, violations...)
+	return result
 }
 
 type showViewModel struct {
 	Title           string
 	DayLength       string
 	TimeWorked      string
+	BreakTime       string
 	TimeRemaining   string
 	EstimatedFinish string
 	Note            string
 	WorkPeriods     []showPeriodViewModel
+	Breaks          []showBreakViewModel
+	Violations      []string
 }
 
 type showPeriodViewModel struct {
@@ -102,5 +188,24 @@ type showPeriodViewModel struct {
 	StartAt    string
 	EndAt      string
 	TimeWorked string
+	Tags       string
 	Note       string
 }
+
+type showBreakViewModel struct {
+	Id       int
+	StartAt  string
+	EndAt    string
+	Duration string
+	Note     string
+}
+
+// formatTags formats tags the way they're written in notes, e.g. "+a +b".
+func formatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "+" + tag
+	}
+
+	return strings.Join(formatted, " ")
+}
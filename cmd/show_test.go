@@ -49,13 +49,14 @@ September 01, 2023 (Fri)
 
 Work Day: 		7h30m
 Time Worked:		0m
+Break Time:		0m
 Time Remaining:		7h30m
 Estimated Finish:	:estFinish
 Note:			This is a note.
 
 
 WORK PERIODS
-ID	START			END			TIME WORKED	NOTE
+ID	START			END			TIME WORKED	TAGS			NOTE
 
 `,
 			map[string]string{
@@ -69,6 +70,7 @@ ID	START			END			TIME WORKED	NOTE
 		wp1.StartAt = time.Date(2023, 9, 1, 9, 0, 0, 0, time.Local)
 		wp1.SetEndAt(wp1.StartAt.Add(1 * time.Hour))
 		wp1.SetNote("Period note.")
+		wp1.AddTags("meeting", "acme")
 
 		_, err = repo.CreateWorkPeriod(wp1)
 		testutil.AssertNoErr(t, err)
@@ -92,15 +94,16 @@ September 01, 2023 (Fri)
 
 Work Day: 		7h30m
 Time Worked:		1h30m
+Break Time:		0m
 Time Remaining:		6h0m
 Estimated Finish:	:estFinish
 Note:			This is a note.
 
 
 WORK PERIODS
-ID	START			END			TIME WORKED	NOTE
-1	2023-09-01 9:00 AM	2023-09-01 10:00 AM 	1h0m		Period note.
-2	2023-09-01 10:00 AM	2023-09-01 10:30 AM 	30m	:tab
+ID	START			END			TIME WORKED	TAGS			NOTE
+1	2023-09-01 9:00 AM	2023-09-01 10:00 AM 	1h0m		+acme +meeting  	Period note.
+2	2023-09-01 10:00 AM	2023-09-01 10:30 AM 	30m		                :tab
 
 `,
 			map[string]string{
@@ -110,6 +113,96 @@ ID	START			END			TIME WORKED	NOTE
 			},
 		)
 	})
+
+	t.Run("with breaks", func(t *testing.T) {
+		b := model.NewBreak(wd)
+		b.StartAt = time.Date(2023, 9, 1, 9, 30, 0, 0, time.Local)
+		b.SetEndAt(b.StartAt.Add(15 * time.Minute))
+		b.SetNote("Coffee.")
+		_, err = repo.CreateBreak(b)
+		testutil.AssertNoErr(t, err)
+
+		out := &bytes.Buffer{}
+		err = runShowCmd(out, repo, "2023-09-01")
+		testutil.AssertNoErr(t, err)
+
+		compareShowOutput(
+			t,
+			out.String(),
+			`
+September 01, 2023 (Fri)
+========================
+
+Work Day: 		7h30m
+Time Worked:		1h15m
+Break Time:		15m
+Time Remaining:		6h15m
+Estimated Finish:	:estFinish
+Note:			This is a note.
+
+
+WORK PERIODS
+ID	START			END			TIME WORKED	TAGS			NOTE
+1	2023-09-01 9:00 AM	2023-09-01 10:00 AM 	1h0m		+acme +meeting  	Period note.
+2	2023-09-01 10:00 AM	2023-09-01 10:30 AM 	30m		                :tab
+
+BREAKS
+ID	START			END			DURATION	NOTE
+1	2023-09-01 9:30 AM	2023-09-01 9:45 AM  	15m		Coffee.
+
+`,
+			map[string]string{
+				"estFinish": util.FormatDateTime(time.Now().Add(6*time.Hour + 15*time.Minute)),
+				"tab":       "	",
+			},
+		)
+	})
+}
+
+func TestRunShowCmdWithViolations(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	wd, err := repo.CreateWorkDay(model.NewWorkDay(date))
+	testutil.AssertNoErr(t, err)
+
+	wp := model.NewWorkPeriod(wd)
+	wp.StartAt = date.Add(7 * time.Hour)
+	wp.SetEndAt(date.Add(18 * time.Hour))
+	_, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	out := &bytes.Buffer{}
+	err = runShowCmd(out, repo, "2023-09-01")
+	testutil.AssertNoErr(t, err)
+
+	compareShowOutput(
+		t,
+		out.String(),
+		`
+September 01, 2023 (Fri)
+========================
+
+Work Day: 		7h30m
+Time Worked:		11h0m
+Break Time:		0m
+Time Remaining:		-3h30m
+Estimated Finish:	:estFinish
+
+WORK PERIODS
+ID	START			END			TIME WORKED	TAGS			NOTE
+1	2023-09-01 7:00 AM	2023-09-01 6:00 PM  	11h0m		                :tab
+
+COMPLIANCE
+! worked 11h0m, more than the maximum of 10h0m
+! worked 11h0m with 0m of breaks, at least 30m needed after 6h0m
+
+`,
+		map[string]string{
+			"estFinish": util.FormatDateTime(time.Now().Add(-3*time.Hour - 30*time.Minute)),
+			"tab":       "	",
+		},
+	)
 }
 
 func compareShowOutput(t *testing.T, got string, want string, replacements map[string]string) {
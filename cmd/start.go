@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"time"
 
@@ -18,15 +17,18 @@ type startCmdArgs struct {
 	lengthStr string
 	note      string
 	dayNote   string
+	project   string
+	tags      []string
+	dateStr   string
 }
 
 var startCmd = &cobra.Command{
 	Use:   "start [time]",
 	Short: "Start tracking work hours",
 	Run: func(cmd *cobra.Command, args []string) {
-		repo, err := repository.NewRepo(repository.DefaultDatabasePath)
+		repo, err := openRepo()
 		if err != nil {
-			log.Fatalln(err)
+			fatal(err)
 		}
 
 		var cmdArgs startCmdArgs
@@ -37,9 +39,12 @@ var startCmd = &cobra.Command{
 		cmdArgs.lengthStr = mustGetStringFlag(cmd, "length")
 		cmdArgs.note = mustGetStringFlag(cmd, "note")
 		cmdArgs.dayNote = mustGetStringFlag(cmd, "day-note")
+		cmdArgs.project = mustGetStringFlag(cmd, "project")
+		cmdArgs.tags = mustGetStringArrayFlag(cmd, "tag")
+		cmdArgs.dateStr = mustGetStringFlag(cmd, "date")
 
 		if err := runStartCmd(os.Stdout, repo, cmdArgs); err != nil {
-			log.Fatalln(err)
+			fatal(err)
 		}
 	},
 }
@@ -48,38 +53,72 @@ func init() {
 	startCmd.Flags().StringP("length", "l", "", "work day length (e.g. 4h30m)")
 	startCmd.Flags().StringP("note", "n", "", "work period note")
 	startCmd.Flags().StringP("day-note", "d", "", "work day note")
+	startCmd.Flags().StringP("project", "p", "", "project to attribute the work period to (default default_project from the config file)")
+	startCmd.Flags().String("date", "", "work day to start on, for logging past days (e.g. 2023-09-14, with a clock time such as 09:00)")
+	startCmd.Flags().StringArrayP("tag", "t", nil, "tag for the work period, can be repeated (or write +tag in the note)")
 
 	rootCmd.AddCommand(startCmd)
 }
 
 func runStartCmd(out io.Writer, repo *repository.Repo, args startCmdArgs) error {
-	midnight := util.TodayAtMidnight()
-	startAt, err := util.ParseTimeString(args.timeStr)
+	date, err := parseDateFlag(args.dateStr)
+	if err != nil {
+		return err
+	}
+
+	startAt, err := parseTimeOnDate(args.timeStr, date, args.dateStr != "")
 	if err != nil {
 		return fmt.Errorf("error parsing time string:, %v", err)
 	}
 
-	workDay, err := repo.GetWorkDayByDate(midnight)
+	project, err := resolveProject(repo, args.project)
+	if err != nil {
+		return err
+	}
+
+	period := model.WorkPeriod{StartAt: startAt}
+	if args.note != "" {
+		period.SetNote(args.note)
+	}
+	period.SetProject(project)
+	period.AddTags(args.tags...)
+	period.AddTags(model.ParseNoteTags(args.note)...)
+
+	return startWorkPeriod(out, repo, date, period, args.lengthStr, args.dayNote)
+}
+
+// startWorkPeriod creates the work period on the date's work day, creating the
+// work day with the given length and note if it doesn't exist yet. Nothing is
+// created while another work period is still open.
+func startWorkPeriod(out io.Writer, repo *repository.Repo, date time.Time, period model.WorkPeriod, lengthStr string, dayNote string) error {
+	workDay, err := repo.GetWorkDayByDate(date)
 	if err != nil {
 		return fmt.Errorf("error loading work day: %v", err)
 	}
 
-	workPeriods, err := repo.GetWorkPeriods(workDay)
+	open, err := repo.GetOpenWorkPeriod()
 	if err != nil {
-		return fmt.Errorf("error loading work periods: %v", err)
+		return fmt.Errorf("error loading open work period: %v", err)
 	}
-	for _, wp := range workPeriods {
-		if wp.EndAt.Time.IsZero() {
-			fmt.Fprintln(out, "This work day already has an open work period.")
-			return nil
+
+	if open.Id != 0 {
+		if open.WorkDayId == workDay.Id {
+			return printMessage(out, "This work day already has an open work period.\n")
 		}
+
+		return printMessage(out, "A work period started %s is still open.\n", util.FormatDateTime(open.StartAt))
 	}
 
 	outFormatString := "Started tracking time on work day #%d (%s).\n"
-	if workDay.Id == 0 {
-		workDay = model.NewWorkDay(midnight)
-		if args.lengthStr != "" {
-			duration, err := time.ParseDuration(args.lengthStr)
+	newDay := workDay.Id == 0
+	if newDay {
+		workDay, err = newWorkDay(repo, date)
+		if err != nil {
+			return err
+		}
+
+		if lengthStr != "" {
+			duration, err := time.ParseDuration(lengthStr)
 			if err != nil {
 				return fmt.Errorf("error parsing length string: %v", err)
 			}
@@ -87,8 +126,8 @@ func runStartCmd(out io.Writer, repo *repository.Repo, args startCmdArgs) error
 			workDay.LengthMins = int(duration.Minutes())
 		}
 
-		if args.dayNote != "" {
-			workDay.SetNote(args.dayNote)
+		if dayNote != "" {
+			workDay.SetNote(dayNote)
 		}
 
 		workDay, err = repo.CreateWorkDay(workDay)
@@ -99,16 +138,20 @@ func runStartCmd(out io.Writer, repo *repository.Repo, args startCmdArgs) error
 		outFormatString = "Started tracking time on NEW work day #%d (%s).\n"
 	}
 
-	period := model.WorkPeriod{WorkDayId: workDay.Id, StartAt: startAt}
-	if args.note != "" {
-		period.SetNote(args.note)
-	}
-
-	_, err = repo.CreateWorkPeriod(period)
+	period.WorkDayId = workDay.Id
+	period, err = repo.CreateWorkPeriod(period)
 	if err != nil {
 		return fmt.Errorf("error creating work period: %v", err)
 	}
 
-	fmt.Fprintf(out, outFormatString, workDay.Id, util.FormatDate(workDay.Date))
-	return nil
+	result := startResult{workPeriodResult: newWorkPeriodResult(period), Date: workDay.Date.Format(util.DateFormatStr), NewWorkDay: newDay}
+	return printResult(out, result, outFormatString, workDay.Id, util.FormatDate(workDay.Date))
+}
+
+// startResult is the work period started by start, switch and resume, with
+// the date of its work day and whether that work day was created for it.
+type startResult struct {
+	workPeriodResult
+	Date       string `json:"date"`
+	NewWorkDay bool   `json:"new_work_day"`
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -136,7 +137,7 @@ func TestRunStartCmdNoWorkDay(t *testing.T) {
 			wantWorkDay: model.WorkDay{
 				Id:         1,
 				Date:       midnight,
-				LengthMins: model.DefaultDayLengthMins,
+				LengthMins: defaultLengthOn(midnight),
 				Note:       sql.NullString{Valid: false, String: ""},
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
@@ -177,7 +178,7 @@ func TestRunStartCmdNoWorkDay(t *testing.T) {
 			wantWorkDay: model.WorkDay{
 				Id:         1,
 				Date:       midnight,
-				LengthMins: model.DefaultDayLengthMins,
+				LengthMins: defaultLengthOn(midnight),
 				Note:       sql.NullString{Valid: true, String: "This is a note."},
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
@@ -219,3 +220,170 @@ func TestRunStartCmdNoWorkDay(t *testing.T) {
 		})
 	}
 }
+
+func TestRunStartCmdWithProject(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	acme, err := repo.CreateProject(model.NewProject("acme"))
+	testutil.AssertNoErr(t, err)
+
+	globex, err := repo.CreateProject(model.NewProject("globex"))
+	testutil.AssertNoErr(t, err)
+
+	archived := model.NewProject("initech")
+	archived.Archive()
+	_, err = repo.CreateProject(archived)
+	testutil.AssertNoErr(t, err)
+
+	prevCfg := cfg
+	t.Cleanup(func() { cfg = prevCfg })
+
+	testCases := []struct {
+		title          string
+		project        string
+		defaultProject string
+		wantProjectId  sql.NullInt64
+		wantErr        bool
+	}{
+		{title: "no project"},
+		{title: "project flag", project: "acme", wantProjectId: sql.NullInt64{Valid: true, Int64: int64(acme.Id)}},
+		{title: "default project", defaultProject: "globex", wantProjectId: sql.NullInt64{Valid: true, Int64: int64(globex.Id)}},
+		{title: "project flag over default", project: "acme", defaultProject: "globex", wantProjectId: sql.NullInt64{Valid: true, Int64: int64(acme.Id)}},
+		{title: "unknown project", project: "umbrella", wantErr: true},
+		{title: "archived project", project: "initech", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			cfg.DefaultProject = tc.defaultProject
+
+			err := runStartCmd(&bytes.Buffer{}, repo, startCmdArgs{project: tc.project})
+			if tc.wantErr {
+				if err == nil {
+					t.Error("Expected an error but got none")
+				}
+				return
+			}
+			testutil.AssertNoErr(t, err)
+
+			period, err := repo.GetOpenWorkPeriod()
+			testutil.AssertNoErr(t, err)
+
+			if period.ProjectId != tc.wantProjectId {
+				t.Errorf("got project ID %+v, want %+v", period.ProjectId, tc.wantProjectId)
+			}
+
+			period.SetEndAt(time.Now())
+			_, err = repo.UpdateWorkPeriod(period)
+			testutil.AssertNoErr(t, err)
+		})
+	}
+}
+
+func TestRunStartCmdWithTags(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	args := startCmdArgs{tags: []string{"Acme", "+review"}, note: "Reviewed PRs +review +code"}
+	err := runStartCmd(&bytes.Buffer{}, repo, args)
+	testutil.AssertNoErr(t, err)
+
+	period, err := repo.GetOpenWorkPeriod()
+	testutil.AssertNoErr(t, err)
+
+	want := []string{"acme", "code", "review"}
+	if !reflect.DeepEqual(period.Tags, want) {
+		t.Errorf("got tags %v, want %v", period.Tags, want)
+	}
+}
+
+func TestRunStartCmdWithOpenPeriodFromYesterday(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	yesterday := util.TodayAtMidnight().AddDate(0, 0, -1)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(yesterday))
+	testutil.AssertNoErr(t, err)
+
+	open := model.NewWorkPeriod(workDay)
+	open.StartAt = yesterday.Add(22 * time.Hour)
+	_, err = repo.CreateWorkPeriod(open)
+	testutil.AssertNoErr(t, err)
+
+	out := &bytes.Buffer{}
+	err = runStartCmd(out, repo, startCmdArgs{})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, fmt.Sprintf("A work period started %s is still open.\n", util.FormatDateTime(open.StartAt)))
+
+	count, err := repo.GetWorkDayCount()
+	testutil.AssertNoErr(t, err)
+	if count != 1 {
+		t.Errorf("got %d work days, want 1", count)
+	}
+}
+
+func TestRunStartCmdWithDate(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 14, 0, 0, 0, 0, time.Local)
+
+	out := &bytes.Buffer{}
+	err := runStartCmd(out, repo, startCmdArgs{dateStr: "2023-09-14", timeStr: "09:00"})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Started tracking time on NEW work day #1 (2023-09-14).\n")
+
+	period, err := repo.GetOpenWorkPeriod()
+	testutil.AssertNoErr(t, err)
+	if want := date.Add(9 * time.Hour); !period.StartAt.Equal(want) {
+		t.Errorf("got start %v, want %v", period.StartAt, want)
+	}
+
+	out = &bytes.Buffer{}
+	err = runStopCmd(out, repo, stopCmdArgs{dateStr: "2023-09-14", timeStr: "17:00"})
+	testutil.AssertNoErr(t, err)
+
+	period, err = repo.GetWorkPeriodById(period.Id)
+	testutil.AssertNoErr(t, err)
+	if want := date.Add(17 * time.Hour); !period.EndAt.Valid || !period.EndAt.Time.Equal(want) {
+		t.Errorf("got end %v, want %v", period.EndAt, want)
+	}
+}
+
+func TestRunStartCmdWithDateRequiresClockTime(t *testing.T) {
+	for _, timeStr := range []string{"", "now", "-30m"} {
+		t.Run(timeStr, func(t *testing.T) {
+			repo := testutil.NewRepo(t)
+
+			err := runStartCmd(&bytes.Buffer{}, repo, startCmdArgs{dateStr: "2023-09-14", timeStr: timeStr})
+			if err == nil {
+				t.Fatal("Expected an error but got none")
+			}
+
+			count, err := repo.GetWorkDayCount()
+			testutil.AssertNoErr(t, err)
+			if count != 0 {
+				t.Errorf("got %d work days, want 0", count)
+			}
+		})
+	}
+}
+
+func TestRunStopCmdWithDateRequiresClockTime(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 14, 0, 0, 0, 0, time.Local)
+
+	err := runStartCmd(&bytes.Buffer{}, repo, startCmdArgs{dateStr: "2023-09-14", timeStr: "09:00"})
+	testutil.AssertNoErr(t, err)
+
+	for _, timeStr := range []string{"", "now", "-30m"} {
+		t.Run(timeStr, func(t *testing.T) {
+			err := runStopCmd(&bytes.Buffer{}, repo, stopCmdArgs{dateStr: "2023-09-14", timeStr: timeStr})
+			if err == nil {
+				t.Fatal("Expected an error but got none")
+			}
+
+			period, err := repo.GetOpenWorkPeriod()
+			testutil.AssertNoErr(t, err)
+			if !period.StartAt.Equal(date.Add(9 * time.Hour)) {
+				t.Errorf("Expected the work period started at 09:00 to still be open, got %+v", period)
+			}
+		})
+	}
+}
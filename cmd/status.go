@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+// statusNotRunningExitCode is returned by the status command when no work
+// period is open, so that scripts can tell it apart from errors.
+const statusNotRunningExitCode int = 3
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows whether a work period is currently running",
+	Long: fmt.Sprintf(`Shows whether a work period is currently running.
+
+Exits with status %d when no work period is running.`, statusNotRunningExitCode),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		running, err := runStatusCmd(os.Stdout, repo)
+		if err != nil {
+			fatal(err)
+		}
+
+		if !running {
+			os.Exit(statusNotRunningExitCode)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatusCmd(out io.Writer, repo *repository.Repo) (bool, error) {
+	period, err := repo.GetOpenWorkPeriod()
+	if err != nil {
+		return false, fmt.Errorf("error loading open work period: %v", err)
+	}
+
+	// An open work period may have started on a previous work day, in which
+	// case that's the day its time counts towards.
+	var workDay model.WorkDay
+	if period.Id != 0 {
+		workDay, err = repo.GetWorkDayById(period.WorkDayId)
+	} else {
+		workDay, err = repo.GetWorkDayByDate(util.TodayAtMidnight())
+	}
+	if err != nil {
+		return false, fmt.Errorf("error loading work day: %v", err)
+	}
+
+	if workDay.Id == 0 {
+		if err := printResult(out, statusResult{workPeriodResult: workPeriodResult{Tags: []string{}}}, "Not running. No work day for today yet.\n"); err != nil {
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	workPeriods, err := repo.GetWorkPeriods(workDay)
+	if err != nil {
+		return false, fmt.Errorf("error loading work periods: %v", err)
+	}
+	workDay.SetWorkPeriods(workPeriods)
+
+	breaks, err := repo.GetBreaks(workDay)
+	if err != nil {
+		return false, fmt.Errorf("error loading breaks: %v", err)
+	}
+	workDay.SetBreaks(breaks)
+
+	result := statusResult{
+		workPeriodResult:  workPeriodResult{Tags: []string{}},
+		Date:              workDay.Date.Format(util.DateFormatStr),
+		TimeWorkedMins:    int(workDay.TimeWorked().Minutes()),
+		TimeRemainingMins: int(workDay.TimeRemaining().Minutes()),
+	}
+
+	if period.Id == 0 {
+		if err := printResult(out, result, "Not running. Today: %s worked, %s remaining.\n", util.FormatDuration(workDay.TimeWorked()), util.FormatDuration(workDay.TimeRemaining())); err != nil {
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	result.Running = true
+	result.workPeriodResult = newWorkPeriodResult(period)
+	result.EstimatedFinish = workDay.EstimatedFinish().Format(time.RFC3339)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Running since %s (%s)", util.FormatDateTime(period.StartAt), util.FormatDuration(time.Since(period.StartAt)))
+	if period.Note.Valid {
+		fmt.Fprintf(&text, ": %s", period.Note.String)
+	}
+	fmt.Fprintln(&text, ".")
+
+	openBreak, err := repo.GetOpenBreak()
+	if err != nil {
+		return false, fmt.Errorf("error loading open break: %v", err)
+	}
+
+	if openBreak.Id != 0 {
+		result.BreakStartAt = openBreak.StartAt.Format(time.RFC3339)
+		fmt.Fprintf(&text, "On a break since %s (%s).\n", util.FormatDateTime(openBreak.StartAt), util.FormatDuration(openBreak.Duration()))
+	}
+
+	dayLabel := "Today"
+	if !workDay.Date.Equal(util.TodayAtMidnight()) {
+		dayLabel = util.FormatDate(workDay.Date)
+	}
+
+	fmt.Fprintf(
+		&text,
+		"%s: %s worked, %s remaining, estimated finish %s.\n",
+		dayLabel,
+		util.FormatDuration(workDay.TimeWorked()),
+		util.FormatDuration(workDay.TimeRemaining()),
+		util.FormatDateTime(workDay.EstimatedFinish()),
+	)
+
+	if err := printResult(out, result, "%s", text.String()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// statusResult is the open work period, if any, and the time worked on its
+// work day or today. The work day's date is empty when there is none.
+type statusResult struct {
+	Running bool `json:"running"`
+	workPeriodResult
+	BreakStartAt      string `json:"break_start_at"`
+	Date              string `json:"date"`
+	TimeWorkedMins    int    `json:"time_worked_mins"`
+	TimeRemainingMins int    `json:"time_remaining_mins"`
+	EstimatedFinish   string `json:"estimated_finish"`
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunStatusCmdNoWorkDay(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	running, err := runStatusCmd(out, repo)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Not running. No work day for today yet.\n")
+
+	if running {
+		t.Error("Expected status to not be running")
+	}
+}
+
+func TestRunStatusCmd(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	closed := model.NewWorkPeriod(workDay)
+	closed.StartAt = time.Now().Add(-3 * time.Hour)
+	closed.SetEndAt(closed.StartAt.Add(time.Hour))
+	_, err = repo.CreateWorkPeriod(closed)
+	testutil.AssertNoErr(t, err)
+
+	t.Run("not running", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		running, err := runStatusCmd(out, repo)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertOutput(t, out, "Not running. Today: 1h0m worked, 6h30m remaining.\n")
+
+		if running {
+			t.Error("Expected status to not be running")
+		}
+	})
+
+	t.Run("running", func(t *testing.T) {
+		open := model.NewWorkPeriod(workDay)
+		open.StartAt = time.Now().Add(-30 * time.Minute)
+		open.SetNote("Reviewing")
+		_, err = repo.CreateWorkPeriod(open)
+		testutil.AssertNoErr(t, err)
+
+		out := &bytes.Buffer{}
+		running, err := runStatusCmd(out, repo)
+		testutil.AssertNoErr(t, err)
+
+		gotLines := strings.Split(out.String(), "\n")
+		wantLines := []string{
+			fmt.Sprintf("Running since %s (30m): Reviewing", util.FormatDateTime(open.StartAt)),
+			"Today: 1h30m worked, ",
+		}
+		for i, want := range wantLines {
+			if !strings.HasPrefix(gotLines[i], want) {
+				t.Errorf("line %d: got '%s', want prefix '%s'", i+1, gotLines[i], want)
+			}
+		}
+
+		if !running {
+			t.Error("Expected status to be running")
+		}
+	})
+}
+
+func TestRunStatusCmdAcrossMidnight(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	yesterday := util.TodayAtMidnight().AddDate(0, 0, -1)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(yesterday))
+	testutil.AssertNoErr(t, err)
+
+	open := model.NewWorkPeriod(workDay)
+	open.StartAt = yesterday.Add(22 * time.Hour)
+	_, err = repo.CreateWorkPeriod(open)
+	testutil.AssertNoErr(t, err)
+
+	out := &bytes.Buffer{}
+	running, err := runStatusCmd(out, repo)
+	testutil.AssertNoErr(t, err)
+
+	gotLines := strings.Split(out.String(), "\n")
+	wantLines := []string{
+		fmt.Sprintf("Running since %s (", util.FormatDateTime(open.StartAt)),
+		fmt.Sprintf("%s: ", util.FormatDate(yesterday)),
+	}
+	for i, want := range wantLines {
+		if !strings.HasPrefix(gotLines[i], want) {
+			t.Errorf("line %d: got '%s', want prefix '%s'", i+1, gotLines[i], want)
+		}
+	}
+
+	if !running {
+		t.Error("Expected status to be running")
+	}
+}
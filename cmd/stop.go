@@ -4,69 +4,124 @@ import (
 	"database/sql"
 	"fmt"
 	"io"
-	"log"
 	"os"
+	"strings"
 
+	"github.com/robyparr/wh/model"
 	"github.com/robyparr/wh/repository"
 	"github.com/robyparr/wh/util"
 	"github.com/spf13/cobra"
 )
 
+type stopCmdArgs struct {
+	timeStr string
+	note    string
+	dateStr string
+}
+
 var stopCmd = &cobra.Command{
 	Use:   "stop [time]",
 	Short: "Stop tracking work hours",
 	Run: func(cmd *cobra.Command, args []string) {
-		repo, err := repository.NewRepo(repository.DefaultDatabasePath)
+		repo, err := openRepo()
 		if err != nil {
-			log.Fatalln(err)
+			fatal(err)
 		}
 
-		var timeStr string
+		var cmdArgs stopCmdArgs
 		if len(args) != 0 {
-			timeStr = args[0]
+			cmdArgs.timeStr = args[0]
 		}
 
-		note := mustGetStringFlag(cmd, "note")
-		if err := runStopCmd(os.Stdout, repo, timeStr, note); err != nil {
-			log.Fatalln(err)
+		cmdArgs.note = mustGetStringFlag(cmd, "note")
+		cmdArgs.dateStr = mustGetStringFlag(cmd, "date")
+		if err := runStopCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
 		}
 	},
 }
 
 func init() {
 	stopCmd.Flags().StringP("note", "n", "", "work period note")
+	stopCmd.Flags().String("date", "", "date the work period ends on, for logging past days (e.g. 2023-09-14, with a clock time such as 17:00)")
 	rootCmd.AddCommand(stopCmd)
 }
 
-func runStopCmd(out io.Writer, repo *repository.Repo, timeStr string, note string) error {
-	workDay, err := repo.GetWorkDayByDate(util.TodayAtMidnight())
+func runStopCmd(out io.Writer, repo *repository.Repo, args stopCmdArgs) error {
+	period, err := repo.GetOpenWorkPeriod()
 	if err != nil {
 		return err
 	}
 
-	period, err := repo.GetOpenWorkPeriod(workDay)
+	if period.Id == 0 {
+		return printMessage(out, "Unable to find an ongoing work period.\n")
+	}
+
+	date, err := parseDateFlag(args.dateStr)
 	if err != nil {
 		return err
 	}
 
-	if period.Id == 0 {
-		fmt.Fprintln(out, "Unable to find an ongoing work period.")
-		return nil
+	endAt, err := parseTimeOnDate(args.timeStr, date, args.dateStr != "")
+	if err != nil {
+		return err
 	}
 
-	endAt, err := util.ParseTimeString(timeStr)
+	period.EndAt = sql.NullTime{Valid: true, Time: endAt}
+	if err := period.Validate(); err != nil {
+		return err
+	}
+
+	if args.note != "" {
+		period.SetNote(args.note)
+		period.AddTags(model.ParseNoteTags(args.note)...)
+	}
+
+	// Stopping work also ends a break that's still going.
+	openBreak, err := repo.GetOpenBreak()
 	if err != nil {
 		return err
 	}
 
-	period.EndAt = sql.NullTime{Valid: true, Time: endAt}
-	if note != "" {
-		period.SetNote(note)
+	if openBreak.Id != 0 && endAt.Before(openBreak.StartAt) {
+		return fmt.Errorf("work period must not end before the ongoing break started at %s", util.FormatDateTime(openBreak.StartAt))
 	}
 
-	if _, err := repo.UpdateWorkPeriod(period); err != nil {
+	period, err = repo.UpdateWorkPeriod(period)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if openBreak.Id != 0 {
+		openBreak.SetEndAt(endAt)
+		if _, err := repo.UpdateBreak(openBreak); err != nil {
+			return err
+		}
+	}
+
+	workDay, err := repo.GetWorkDayById(period.WorkDayId)
+	if err != nil {
+		return err
+	}
+
+	violations, err := checkCompliance(repo, workDay.Date, workDay.Date)
+	if err != nil {
+		return err
+	}
+
+	result := stopResult{workPeriodResult: newWorkPeriodResult(period), Warnings: []string{}}
+	var text strings.Builder
+	for _, v := range violations {
+		result.Warnings = append(result.Warnings, v.Message)
+		fmt.Fprintf(&text, "Warning: %s.\n", v.Message)
+	}
+
+	return printResult(out, result, "%s", text.String())
+}
+
+// stopResult is the stopped work period and the working time rules its work
+// day now breaks.
+type stopResult struct {
+	workPeriodResult
+	Warnings []string `json:"warnings"`
 }
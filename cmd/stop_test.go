@@ -3,9 +3,11 @@ package cmd
 import (
 	"bytes"
 	"database/sql"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/robyparr/wh/config"
 	"github.com/robyparr/wh/model"
 	"github.com/robyparr/wh/util"
 	"github.com/robyparr/wh/util/testutil"
@@ -15,12 +17,19 @@ func TestRunStopCmd(t *testing.T) {
 	out := &bytes.Buffer{}
 	repo := testutil.NewRepo(t)
 
+	// The work periods here overlap and run for however long is left of the
+	// day, so leave the working time rules to TestRunStopCmdWarnings.
+	prevCfg := cfg
+	t.Cleanup(func() { cfg = prevCfg })
+	cfg.Compliance = config.ComplianceConfig{}
+
 	midnight := util.TodayAtMidnight()
 	workDay, err := repo.CreateWorkDay(model.NewWorkDay(midnight))
 	testutil.AssertNoErr(t, err)
 
 	testCases := []struct {
 		name       string
+		startAt    time.Time
 		timeStr    string
 		note       string
 		wantPeriod model.WorkPeriod
@@ -62,11 +71,12 @@ func TestRunStopCmd(t *testing.T) {
 		},
 		{
 			name:    "with past relative time arg",
+			startAt: time.Now().Add(-2 * time.Hour),
 			timeStr: "-1h30m",
 			wantPeriod: model.WorkPeriod{
 				Id:        4,
 				WorkDayId: 1,
-				StartAt:   time.Now(),
+				StartAt:   time.Now().Add(-2 * time.Hour),
 				EndAt:     sql.NullTime{Valid: true, Time: time.Now().Add(-90 * time.Minute)},
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
@@ -89,10 +99,15 @@ func TestRunStopCmd(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err = repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
+			period := model.NewWorkPeriod(workDay)
+			if !tc.startAt.IsZero() {
+				period.StartAt = tc.startAt
+			}
+
+			_, err = repo.CreateWorkPeriod(period)
 			testutil.AssertNoErr(t, err)
 
-			err = runStopCmd(out, repo, tc.timeStr, tc.note)
+			err = runStopCmd(out, repo, stopCmdArgs{timeStr: tc.timeStr, note: tc.note})
 			testutil.AssertNoErr(t, err)
 
 			got := out.String()
@@ -114,7 +129,7 @@ func TestRunStopCmdNoWorkDay(t *testing.T) {
 	out := &bytes.Buffer{}
 	repo := testutil.NewRepo(t)
 
-	err := runStopCmd(out, repo, "", "")
+	err := runStopCmd(out, repo, stopCmdArgs{})
 	testutil.AssertNoErr(t, err)
 
 	got := out.String()
@@ -137,7 +152,7 @@ func TestRunStopCmdNoOpenPeriod(t *testing.T) {
 	_, err = repo.CreateWorkPeriod(period)
 	testutil.AssertNoErr(t, err)
 
-	err = runStopCmd(out, repo, "", "")
+	err = runStopCmd(out, repo, stopCmdArgs{})
 	testutil.AssertNoErr(t, err)
 
 	got := out.String()
@@ -154,3 +169,97 @@ func TestRunStopCmdNoOpenPeriod(t *testing.T) {
 		t.Errorf("got %d work periods, want 1", gotPeriodCount)
 	}
 }
+
+func TestRunStopCmdBeforeStart(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(util.TodayAtMidnight()))
+	testutil.AssertNoErr(t, err)
+
+	_, err = repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
+	testutil.AssertNoErr(t, err)
+
+	err = runStopCmd(&bytes.Buffer{}, repo, stopCmdArgs{timeStr: "-1h"})
+	if err != model.ErrEndBeforeStart {
+		t.Errorf("got error %v, want %v", err, model.ErrEndBeforeStart)
+	}
+
+	period, err := repo.GetOpenWorkPeriod()
+	testutil.AssertNoErr(t, err)
+	if period.Id == 0 {
+		t.Error("Expected the work period to still be open")
+	}
+}
+
+func TestRunStopCmdWithNoteTags(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(util.TodayAtMidnight()))
+	testutil.AssertNoErr(t, err)
+
+	period := model.NewWorkPeriod(workDay)
+	period.AddTags("acme")
+	period, err = repo.CreateWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	err = runStopCmd(&bytes.Buffer{}, repo, stopCmdArgs{note: "Standup +meeting"})
+	testutil.AssertNoErr(t, err)
+
+	got, err := repo.GetWorkPeriodById(period.Id)
+	testutil.AssertNoErr(t, err)
+
+	want := []string{"acme", "meeting"}
+	if !reflect.DeepEqual(got.Tags, want) {
+		t.Errorf("got tags %v, want %v", got.Tags, want)
+	}
+}
+
+func TestRunStopCmdAcrossMidnight(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	yesterday := util.TodayAtMidnight().AddDate(0, 0, -1)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(yesterday))
+	testutil.AssertNoErr(t, err)
+
+	period := model.NewWorkPeriod(workDay)
+	period.StartAt = yesterday.Add(22 * time.Hour)
+	_, err = repo.CreateWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	out := &bytes.Buffer{}
+	err = runStopCmd(out, repo, stopCmdArgs{timeStr: "01:00"})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "")
+
+	workDays, err := repo.GetWorkDaysInRange(yesterday, util.TodayAtMidnight())
+	testutil.AssertNoErr(t, err)
+
+	if len(workDays) != 1 {
+		t.Fatalf("got %d work days, want 1", len(workDays))
+	}
+
+	if got, want := workDays[0].TimeWorked(), 3*time.Hour; got != want {
+		t.Errorf("got %s worked on the start day, want %s", got, want)
+	}
+}
+
+func TestRunStopCmdWarnings(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	prevCfg := cfg
+	t.Cleanup(func() { cfg = prevCfg })
+	cfg.Compliance = config.ComplianceConfig{MaxDaily: config.Duration(time.Hour)}
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(util.TodayAtMidnight()))
+	testutil.AssertNoErr(t, err)
+
+	period := model.NewWorkPeriod(workDay)
+	period.StartAt = time.Now().Add(-2 * time.Hour)
+	_, err = repo.CreateWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	out := &bytes.Buffer{}
+	err = runStopCmd(out, repo, stopCmdArgs{})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Warning: worked 2h0m, more than the maximum of 1h0m.\n")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
+	"github.com/spf13/cobra"
+)
+
+type switchCmdArgs struct {
+	timeStr string
+	note    string
+	project string
+	tags    []string
+}
+
+var switchCmd = &cobra.Command{
+	Use:   "switch [time]",
+	Short: "Stop the ongoing work period and start a new one at the same time",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, err := openRepo()
+		if err != nil {
+			fatal(err)
+		}
+
+		var cmdArgs switchCmdArgs
+		if len(args) > 0 {
+			cmdArgs.timeStr = args[0]
+		}
+
+		cmdArgs.note = mustGetStringFlag(cmd, "note")
+		cmdArgs.project = mustGetStringFlag(cmd, "project")
+		cmdArgs.tags = mustGetStringArrayFlag(cmd, "tag")
+
+		if err := runSwitchCmd(os.Stdout, repo, cmdArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	switchCmd.Flags().StringP("note", "n", "", "note for the new work period")
+	switchCmd.Flags().StringP("project", "p", "", "project to attribute the new work period to (default default_project from the config file)")
+	switchCmd.Flags().StringArrayP("tag", "t", nil, "tag for the new work period, can be repeated (or write +tag in the note)")
+
+	rootCmd.AddCommand(switchCmd)
+}
+
+func runSwitchCmd(out io.Writer, repo *repository.Repo, args switchCmdArgs) error {
+	open, err := repo.GetOpenWorkPeriod()
+	if err != nil {
+		return fmt.Errorf("error loading work period: %v", err)
+	}
+
+	if open.Id == 0 {
+		return printMessage(out, "Unable to find an ongoing work period.\n")
+	}
+
+	switchAt, err := util.ParseTimeString(args.timeStr)
+	if err != nil {
+		return fmt.Errorf("error parsing time string: %v", err)
+	}
+
+	open.SetEndAt(switchAt)
+	if err := open.Validate(); err != nil {
+		return err
+	}
+
+	project, err := resolveProject(repo, args.project)
+	if err != nil {
+		return err
+	}
+
+	// The new work period belongs to the work day it starts on, which isn't the
+	// open work period's day when switching after midnight.
+	workDay, err := repo.GetWorkDayById(open.WorkDayId)
+	if err != nil {
+		return fmt.Errorf("error loading work day: %v", err)
+	}
+
+	var newDay bool
+	switchDate := util.TimeAtMidnight(switchAt)
+	if !workDay.Date.Equal(switchDate) {
+		workDay, err = repo.GetWorkDayByDate(switchDate)
+		if err != nil {
+			return fmt.Errorf("error loading work day: %v", err)
+		}
+
+		if workDay.Id == 0 {
+			workDay, err = newWorkDay(repo, switchDate)
+			if err != nil {
+				return err
+			}
+
+			newDay = true
+		}
+	}
+
+	next := model.WorkPeriod{StartAt: switchAt}
+	if args.note != "" {
+		next.SetNote(args.note)
+	}
+	next.SetProject(project)
+	next.AddTags(args.tags...)
+	next.AddTags(model.ParseNoteTags(args.note)...)
+
+	workDay, next, err = repo.SwitchWorkPeriod(open, workDay, next)
+	if err != nil {
+		return fmt.Errorf("error switching work periods: %v", err)
+	}
+
+	result := startResult{workPeriodResult: newWorkPeriodResult(next), Date: workDay.Date.Format(util.DateFormatStr), NewWorkDay: newDay}
+	return printResult(out, result, "Switched work periods at %s.\n", util.FormatTime(switchAt))
+}
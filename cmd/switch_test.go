@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestRunSwitchCmdNoOpenPeriod(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	err := runSwitchCmd(out, repo, switchCmdArgs{})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertOutput(t, out, "Unable to find an ongoing work period.\n")
+}
+
+func TestRunSwitchCmd(t *testing.T) {
+	out := &bytes.Buffer{}
+	repo := testutil.NewRepo(t)
+
+	midnight := util.TodayAtMidnight()
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(midnight))
+	testutil.AssertNoErr(t, err)
+
+	open := model.NewWorkPeriod(workDay)
+	open.StartAt = midnight.Add(time.Minute)
+	open.SetNote("Writing code.")
+	_, err = repo.CreateWorkPeriod(open)
+	testutil.AssertNoErr(t, err)
+
+	args := switchCmdArgs{timeStr: "00:30", note: "Standup +meeting", tags: []string{"team"}}
+	err = runSwitchCmd(out, repo, args)
+	testutil.AssertNoErr(t, err)
+
+	switchAt := midnight.Add(30 * time.Minute)
+	testutil.AssertOutput(t, out, fmt.Sprintf("Switched work periods at %s.\n", util.FormatTime(switchAt)))
+
+	periods, err := repo.GetWorkPeriods(workDay)
+	testutil.AssertNoErr(t, err)
+
+	if len(periods) != 2 {
+		t.Fatalf("Expected 2 work periods, got %d", len(periods))
+	}
+
+	if !periods[0].EndAt.Valid || !periods[0].EndAt.Time.Equal(switchAt) {
+		t.Errorf("got end %v, want %v", periods[0].EndAt, switchAt)
+	}
+
+	if !periods[1].StartAt.Equal(switchAt) {
+		t.Errorf("got start %v, want %v", periods[1].StartAt, switchAt)
+	}
+
+	testutil.AssertEqualStructs(t, periods[1].EndAt, sql.NullTime{})
+	testutil.AssertEqualStructs(t, periods[1].Note, sql.NullString{Valid: true, String: "Standup +meeting"})
+
+	wantTags := []string{"meeting", "team"}
+	if !reflect.DeepEqual(periods[1].Tags, wantTags) {
+		t.Errorf("got tags %v, want %v", periods[1].Tags, wantTags)
+	}
+}
+
+func TestRunSwitchCmdBeforeStart(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	midnight := util.TodayAtMidnight()
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(midnight))
+	testutil.AssertNoErr(t, err)
+
+	open := model.NewWorkPeriod(workDay)
+	open.StartAt = midnight.Add(time.Hour)
+	_, err = repo.CreateWorkPeriod(open)
+	testutil.AssertNoErr(t, err)
+
+	err = runSwitchCmd(&bytes.Buffer{}, repo, switchCmdArgs{timeStr: "00:30"})
+	if err != model.ErrEndBeforeStart {
+		t.Errorf("got error %v, want %v", err, model.ErrEndBeforeStart)
+	}
+
+	periods, err := repo.GetWorkPeriods(workDay)
+	testutil.AssertNoErr(t, err)
+
+	if len(periods) != 1 || periods[0].EndAt.Valid {
+		t.Errorf("Expected the work period to stay open, got %+v", periods)
+	}
+}
+
+func TestRunSwitchCmdAcrossMidnight(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	midnight := util.TodayAtMidnight()
+	yesterday, err := repo.CreateWorkDay(model.NewWorkDay(midnight.AddDate(0, 0, -1)))
+	testutil.AssertNoErr(t, err)
+
+	open := model.NewWorkPeriod(yesterday)
+	open.StartAt = yesterday.Date.Add(23 * time.Hour)
+	_, err = repo.CreateWorkPeriod(open)
+	testutil.AssertNoErr(t, err)
+
+	err = runSwitchCmd(&bytes.Buffer{}, repo, switchCmdArgs{timeStr: "00:00"})
+	testutil.AssertNoErr(t, err)
+
+	today, err := repo.GetWorkDayByDate(midnight)
+	testutil.AssertNoErr(t, err)
+
+	periods, err := repo.GetWorkPeriods(today)
+	testutil.AssertNoErr(t, err)
+
+	if len(periods) != 1 || !periods[0].StartAt.Equal(midnight) {
+		t.Errorf("Expected a work period starting at midnight on today's work day, got %+v", periods)
+	}
+}
@@ -1,16 +1,222 @@
 package cmd
 
 import (
-	"log"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util"
 	"github.com/spf13/cobra"
 )
 
+type dateRangeArgs struct {
+	fromStr string
+	toStr   string
+	week    bool
+	month   bool
+}
+
+// newWorkDay creates a work day whose length comes from the schedule in
+// effect on the date. Without a schedule, weekdays get the configured day
+// length and weekends get nothing.
+func newWorkDay(repo *repository.Repo, date time.Time) (model.WorkDay, error) {
+	schedule, err := repo.GetScheduleForDate(date)
+	if err != nil {
+		return model.WorkDay{}, fmt.Errorf("error loading schedule: %v", err)
+	}
+
+	if schedule.Id == 0 {
+		schedule = model.NewSchedule(date, time.Duration(cfg.DayLength))
+	}
+
+	workDay := model.NewWorkDay(date)
+	workDay.LengthMins = int(schedule.Length(date.Weekday()).Minutes())
+	return workDay, nil
+}
+
+// resolveProject finds the active project with the given name, falling back
+// to the configured default project. It returns an empty project when
+// neither is set.
+func resolveProject(repo *repository.Repo, name string) (model.Project, error) {
+	if name == "" {
+		name = cfg.DefaultProject
+	}
+
+	if name == "" {
+		return model.Project{}, nil
+	}
+
+	project, err := repo.GetProjectByName(name)
+	if err != nil {
+		return model.Project{}, fmt.Errorf("error loading project: %v", err)
+	}
+
+	if project.Id == 0 {
+		return model.Project{}, fmt.Errorf("unknown project '%s'", name)
+	}
+
+	if project.IsArchived() {
+		return model.Project{}, fmt.Errorf("project '%s' is archived", name)
+	}
+
+	return project, nil
+}
+
+// parseDateFlag parses the value of a --date flag, defaulting to today.
+func parseDateFlag(dateStr string) (time.Time, error) {
+	if dateStr == "" {
+		return util.TodayAtMidnight(), nil
+	}
+
+	date, err := util.ParseDateString(dateStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing date: %v", err)
+	}
+
+	return date, nil
+}
+
+// parseTimeOnDate parses the time argument of start and stop. When --date
+// is given the time has to be a clock time, since "now" and offsets from now
+// would land on today instead of the chosen date.
+func parseTimeOnDate(timeStr string, date time.Time, dateSet bool) (time.Time, error) {
+	if dateSet {
+		return util.ParseClockTimeOn(timeStr, date)
+	}
+
+	return util.ParseTimeStringOn(timeStr, date)
+}
+
 func mustGetStringFlag(cmd *cobra.Command, name string) string {
 	str, err := cmd.Flags().GetString(name)
 	if err != nil {
-		log.Fatalf("Error parsing flag '%s': %v", name, err)
+		fatal(fmt.Errorf("error parsing flag '%s': %v", name, err))
 	}
 
 	return str
 }
+
+func mustGetBoolFlag(cmd *cobra.Command, name string) bool {
+	b, err := cmd.Flags().GetBool(name)
+	if err != nil {
+		fatal(fmt.Errorf("error parsing flag '%s': %v", name, err))
+	}
+
+	return b
+}
+
+// confirm asks the user a yes/no question, defaulting to no.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func mustGetStringArrayFlag(cmd *cobra.Command, name string) []string {
+	strs, err := cmd.Flags().GetStringArray(name)
+	if err != nil {
+		fatal(fmt.Errorf("error parsing flag '%s': %v", name, err))
+	}
+
+	return strs
+}
+
+// filterWorkDaysByTags keeps only the work periods that have at least one of
+// the tags, dropping work days left without any. It returns the work days
+// unchanged when no tags are given.
+func filterWorkDaysByTags(workDays []model.WorkDay, tags []string) []model.WorkDay {
+	if len(tags) == 0 {
+		return workDays
+	}
+
+	var filtered []model.WorkDay
+	for _, wd := range workDays {
+		var periods []model.WorkPeriod
+		for _, wp := range wd.WorkPeriods() {
+			if wp.HasAnyTag(tags) {
+				periods = append(periods, wp)
+			}
+		}
+
+		if len(periods) > 0 {
+			wd.SetWorkPeriods(periods)
+			filtered = append(filtered, wd)
+		}
+	}
+
+	return filtered
+}
+
+func addDateRangeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from", "", "first date of the range (e.g. 2023-09-01)")
+	cmd.Flags().String("to", "", "last date of the range (default today)")
+	cmd.Flags().Bool("week", false, "use the current week as the range")
+	cmd.Flags().Bool("month", false, "use the current month as the range")
+	cmd.MarkFlagsMutuallyExclusive("week", "month", "from")
+	cmd.MarkFlagsMutuallyExclusive("week", "month", "to")
+}
+
+func mustGetDateRangeFlags(cmd *cobra.Command) dateRangeArgs {
+	return dateRangeArgs{
+		fromStr: mustGetStringFlag(cmd, "from"),
+		toStr:   mustGetStringFlag(cmd, "to"),
+		week:    mustGetBoolFlag(cmd, "week"),
+		month:   mustGetBoolFlag(cmd, "month"),
+	}
+}
+
+// isSet reports whether any of the date range flags were given.
+func (a dateRangeArgs) isSet() bool {
+	return a.fromStr != "" || a.toStr != "" || a.week || a.month
+}
+
+// resolve returns the first and last dates of the range, both at midnight.
+// Without any flags the range defaults to the current week.
+func (a dateRangeArgs) resolve() (time.Time, time.Time, error) {
+	today := util.TodayAtMidnight()
+
+	switch {
+	case a.month:
+		from := util.StartOfMonth(today)
+		return from, from.AddDate(0, 1, -1), nil
+	case a.fromStr == "" && a.toStr == "", a.week:
+		from := util.StartOfWeek(today)
+		return from, from.AddDate(0, 0, 6), nil
+	}
+
+	from, to := util.StartOfWeek(today), today
+	if a.fromStr != "" {
+		date, err := util.ParseDateString(a.fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("error parsing from date: %v", err)
+		}
+
+		from = date
+	}
+
+	if a.toStr != "" {
+		date, err := util.ParseDateString(a.toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("error parsing to date: %v", err)
+		}
+
+		to = date
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("the from date must not be after the to date")
+	}
+
+	return from, to, nil
+}
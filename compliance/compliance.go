@@ -0,0 +1,136 @@
+// Package compliance checks work days against working time rules such as a
+// maximum number of hours per day, mandatory breaks and rest between days.
+package compliance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+)
+
+const (
+	RuleMaxDaily string = "max-daily"
+	RuleBreak    string = "break"
+	RuleRest     string = "rest"
+)
+
+// Rules are the working time limits work days are checked against. A zero
+// limit disables its rule.
+type Rules struct {
+	// MaxDaily is the most time that may be worked in a day.
+	MaxDaily time.Duration
+	// BreakAfter is how much time may be worked before MinBreak is needed.
+	BreakAfter time.Duration
+	// MinBreak is the least break time needed once BreakAfter is exceeded.
+	MinBreak time.Duration
+	// MinRest is the least time between the end of one work day and the start
+	// of the next.
+	MinRest time.Duration
+}
+
+// Violation is a rule broken on a work day.
+type Violation struct {
+	Date    time.Time
+	Rule    string
+	Message string
+}
+
+// Check checks the work days, which must be ordered by date and have their
+// work periods and breaks set. Rest is checked between consecutive work days.
+func (r Rules) Check(workDays []model.WorkDay) []Violation {
+	var violations []Violation
+	for i, workDay := range workDays {
+		var previous model.WorkDay
+		if i > 0 {
+			previous = workDays[i-1]
+		}
+
+		violations = append(violations, r.CheckDay(workDay, previous)...)
+	}
+
+	return violations
+}
+
+// CheckDay checks a single work day, using the previous work day for the rest
+// rule. previous may be an empty work day.
+func (r Rules) CheckDay(workDay model.WorkDay, previous model.WorkDay) []Violation {
+	var violations []Violation
+	violation := func(rule string, format string, args ...any) {
+		violations = append(violations, Violation{Date: workDay.Date, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	worked := workDay.TimeWorked()
+	if r.MaxDaily > 0 && worked > r.MaxDaily {
+		violation(RuleMaxDaily, "worked %s, more than the maximum of %s", util.FormatDuration(worked), util.FormatDuration(r.MaxDaily))
+	}
+
+	if r.BreakAfter > 0 && worked > r.BreakAfter {
+		if breakTime := breakTime(workDay); breakTime < r.MinBreak {
+			violation(RuleBreak, "worked %s with %s of breaks, at least %s needed after %s", util.FormatDuration(worked), util.FormatDuration(breakTime), util.FormatDuration(r.MinBreak), util.FormatDuration(r.BreakAfter))
+		}
+	}
+
+	if r.MinRest > 0 {
+		previousEnd, ok := lastEnd(previous)
+		start, hasStart := firstStart(workDay)
+		if ok && hasStart {
+			if rest := start.Sub(previousEnd); rest < r.MinRest {
+				violation(RuleRest, "rested %s since the previous work day, less than the minimum of %s", util.FormatDuration(rest), util.FormatDuration(r.MinRest))
+			}
+		}
+	}
+
+	return violations
+}
+
+// breakTime is all time between the first work period's start and the last
+// one's end that wasn't worked, covering both recorded breaks and gaps
+// between work periods.
+func breakTime(workDay model.WorkDay) time.Duration {
+	start, ok := firstStart(workDay)
+	if !ok {
+		return 0
+	}
+
+	end, ok := lastEnd(workDay)
+	if !ok {
+		end = time.Now()
+	}
+
+	// Overlapping work periods can count more time worked than the span.
+	if unworked := end.Sub(start) - workDay.TimeWorked(); unworked > 0 {
+		return unworked
+	}
+
+	return 0
+}
+
+func firstStart(workDay model.WorkDay) (time.Time, bool) {
+	var start time.Time
+	for _, wp := range workDay.WorkPeriods() {
+		if start.IsZero() || wp.StartAt.Before(start) {
+			start = wp.StartAt
+		}
+	}
+
+	return start, !start.IsZero()
+}
+
+// lastEnd returns when the work day's last work period ended. It's false when
+// a work period is still open.
+func lastEnd(workDay model.WorkDay) (time.Time, bool) {
+	var end time.Time
+	for _, wp := range workDay.WorkPeriods() {
+		if !wp.EndAt.Valid {
+			return time.Time{}, false
+		}
+
+		if wp.EndAt.Time.After(end) {
+			end = wp.EndAt.Time
+		}
+	}
+
+	return end, !end.IsZero()
+}
@@ -0,0 +1,101 @@
+package compliance_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/compliance"
+	"github.com/robyparr/wh/model"
+)
+
+var rules = compliance.Rules{
+	MaxDaily:   10 * time.Hour,
+	BreakAfter: 6 * time.Hour,
+	MinBreak:   30 * time.Minute,
+	MinRest:    11 * time.Hour,
+}
+
+// workDay builds a work day on September day, 2023 with a closed work period
+// for each pair of hours, e.g. workDay(1, 9, 12, 13, 17).
+func workDay(day int, hours ...float64) model.WorkDay {
+	date := time.Date(2023, 9, day, 0, 0, 0, 0, time.Local)
+	at := func(hour float64) time.Time {
+		return date.Add(time.Duration(hour * float64(time.Hour)))
+	}
+
+	var periods []model.WorkPeriod
+	for i := 0; i+1 < len(hours); i += 2 {
+		wp := model.WorkPeriod{StartAt: at(hours[i])}
+		wp.SetEndAt(at(hours[i+1]))
+		periods = append(periods, wp)
+	}
+
+	wd := model.NewWorkDay(date)
+	wd.SetWorkPeriods(periods)
+	return wd
+}
+
+func TestCheckDay(t *testing.T) {
+	withBreak := workDay(1, 8, 16)
+	lunch := model.Break{StartAt: withBreak.Date.Add(12 * time.Hour)}
+	lunch.SetEndAt(lunch.StartAt.Add(30 * time.Minute))
+	withBreak.SetBreaks([]model.Break{lunch})
+
+	testCases := []struct {
+		name      string
+		workDay   model.WorkDay
+		previous  model.WorkDay
+		wantRules []string
+	}{
+		{name: "no work periods", workDay: workDay(1)},
+		{name: "short day without a break", workDay: workDay(1, 9, 15)},
+		{name: "gap between work periods", workDay: workDay(1, 8, 12, 12.5, 17)},
+		{name: "recorded break", workDay: withBreak},
+		{name: "long day without a break", workDay: workDay(1, 9, 15.5), wantRules: []string{compliance.RuleBreak}},
+		{name: "gap too short", workDay: workDay(1, 8, 12, 12.25, 17), wantRules: []string{compliance.RuleBreak}},
+		{name: "too long", workDay: workDay(1, 7, 12, 13, 19), wantRules: []string{compliance.RuleMaxDaily}},
+		{name: "enough rest", workDay: workDay(2, 9, 12), previous: workDay(1, 17, 22)},
+		{name: "too little rest", workDay: workDay(2, 7, 12), previous: workDay(1, 17, 22), wantRules: []string{compliance.RuleRest}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotRules []string
+			for _, v := range rules.CheckDay(tc.workDay, tc.previous) {
+				gotRules = append(gotRules, v.Rule)
+				if !v.Date.Equal(tc.workDay.Date) {
+					t.Errorf("got violation date %v, want %v", v.Date, tc.workDay.Date)
+				}
+			}
+
+			if !reflect.DeepEqual(gotRules, tc.wantRules) {
+				t.Errorf("got rules %v, want %v", gotRules, tc.wantRules)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	got := rules.Check([]model.WorkDay{workDay(1, 13, 23), workDay(2, 8, 12)})
+	want := []compliance.Violation{
+		{
+			Date:    time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local),
+			Rule:    compliance.RuleBreak,
+			Message: "worked 10h0m with 0m of breaks, at least 30m needed after 6h0m",
+		},
+		{
+			Date:    time.Date(2023, 9, 2, 0, 0, 0, 0, time.Local),
+			Rule:    compliance.RuleRest,
+			Message: "rested 9h0m since the previous work day, less than the minimum of 11h0m",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	if got := (compliance.Rules{}).Check([]model.WorkDay{workDay(1, 0, 23), workDay(2, 0, 1)}); len(got) != 0 {
+		t.Errorf("Expected disabled rules to find no violations, got %+v", got)
+	}
+}
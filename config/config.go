@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/robyparr/wh/compliance"
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+)
+
+// Config holds the user's preferences, read from a TOML file such as:
+//
+//	day_length = "8h"
+//	time_format = "24h"
+//	date_format = "02.01.2006"
+//	week_start = "sunday"
+//	output = "text"
+//	balance_start = "2023-01-01"
+//	default_project = "acme"
+//
+//	[compliance]
+//	max_daily = "10h"
+//	break_after = "6h"
+//	min_break = "30m"
+//	min_rest = "11h"
+type Config struct {
+	// DayLength is the length of new weekday work days when no schedule applies.
+	DayLength Duration `toml:"day_length"`
+	// TimeFormat is either "12h" or "24h".
+	TimeFormat string `toml:"time_format"`
+	// DateFormat is a Go reference layout used when displaying dates.
+	DateFormat string `toml:"date_format"`
+	// WeekStart is the name of the first day of the week.
+	WeekStart string `toml:"week_start"`
+	// Output is the default output format, one of OutputFormats.
+	Output string `toml:"output"`
+	// BalanceStart is the date the flextime balance starts counting from.
+	BalanceStart string `toml:"balance_start"`
+	// DefaultProject is the name of the project new work periods are
+	// attributed to when no project is given.
+	DefaultProject string `toml:"default_project"`
+	// Compliance holds the working time rules work days are checked against.
+	Compliance ComplianceConfig `toml:"compliance"`
+}
+
+// ComplianceConfig holds the working time rules. Setting a rule to "0s"
+// disables it.
+type ComplianceConfig struct {
+	// MaxDaily is the most time that may be worked in a day.
+	MaxDaily Duration `toml:"max_daily"`
+	// BreakAfter is how much time may be worked before MinBreak is needed.
+	BreakAfter Duration `toml:"break_after"`
+	// MinBreak is the least break time needed after BreakAfter.
+	MinBreak Duration `toml:"min_break"`
+	// MinRest is the least time between two work days.
+	MinRest Duration `toml:"min_rest"`
+}
+
+// OutputFormats lists the valid values of Output.
+var OutputFormats = []string{"text", "json", "tsv"}
+
+func Default() Config {
+	return Config{
+		DayLength:  Duration(time.Duration(model.DefaultDayLengthMins) * time.Minute),
+		TimeFormat: "12h",
+		DateFormat: util.DateFormatStr,
+		WeekStart:  "monday",
+		Output:     "text",
+		Compliance: ComplianceConfig{
+			MaxDaily:   Duration(10 * time.Hour),
+			BreakAfter: Duration(6 * time.Hour),
+			MinBreak:   Duration(30 * time.Minute),
+			MinRest:    Duration(11 * time.Hour),
+		},
+	}
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/wh/config.toml, falling back to
+// ~/.config when XDG_CONFIG_HOME isn't set.
+func DefaultPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, "wh", "config.toml"), nil
+}
+
+// Load reads the config file at path on top of the defaults. A missing file
+// is only an error when mustExist is set.
+func Load(path string, mustExist bool) (Config, error) {
+	cfg := Default()
+
+	_, err := toml.DecodeFile(path, &cfg)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !mustExist {
+			return cfg, nil
+		}
+
+		return Config{}, fmt.Errorf("error reading config file %s: %v", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	return cfg, nil
+}
+
+func (c Config) Validate() error {
+	if c.DayLength < 0 {
+		return errors.New("day_length must not be negative")
+	}
+
+	if c.TimeFormat != "12h" && c.TimeFormat != "24h" {
+		return fmt.Errorf("time_format must be '12h' or '24h', got '%s'", c.TimeFormat)
+	}
+
+	if c.DateFormat == "" {
+		return errors.New("date_format must not be empty")
+	}
+
+	if _, err := c.Weekday(); err != nil {
+		return err
+	}
+
+	if !contains(OutputFormats, c.Output) {
+		return fmt.Errorf("output must be one of %s, got '%s'", strings.Join(OutputFormats, ", "), c.Output)
+	}
+
+	rules := []struct {
+		name     string
+		duration Duration
+	}{
+		{"max_daily", c.Compliance.MaxDaily},
+		{"break_after", c.Compliance.BreakAfter},
+		{"min_break", c.Compliance.MinBreak},
+		{"min_rest", c.Compliance.MinRest},
+	}
+	for _, rule := range rules {
+		if rule.duration < 0 {
+			return fmt.Errorf("compliance.%s must not be negative", rule.name)
+		}
+	}
+
+	if c.BalanceStart != "" {
+		if _, err := util.ParseDateString(c.BalanceStart); err != nil {
+			return fmt.Errorf("balance_start must be a date: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Weekday returns WeekStart as a time.Weekday.
+func (c Config) Weekday() (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(c.WeekStart, day.String()) {
+			return day, nil
+		}
+	}
+
+	return 0, fmt.Errorf("week_start must be the name of a weekday, got '%s'", c.WeekStart)
+}
+
+// UtilSettings returns the formatting settings for the util package.
+func (c Config) UtilSettings() util.Settings {
+	weekStart, _ := c.Weekday()
+
+	return util.Settings{
+		DateFormat:  c.DateFormat,
+		Clock24Hour: c.TimeFormat == "24h",
+		WeekStart:   weekStart,
+	}
+}
+
+// ComplianceRules returns the working time rules for the compliance package.
+func (c Config) ComplianceRules() compliance.Rules {
+	return compliance.Rules{
+		MaxDaily:   time.Duration(c.Compliance.MaxDaily),
+		BreakAfter: time.Duration(c.Compliance.BreakAfter),
+		MinBreak:   time.Duration(c.Compliance.MinBreak),
+		MinRest:    time.Duration(c.Compliance.MinRest),
+	}
+}
+
+// Duration is a time.Duration written as a string (e.g. "7h30m").
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
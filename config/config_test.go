@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/config"
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	testutil.AssertNoErr(t, os.WriteFile(path, []byte(contents), 0o644))
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("missing optional file", func(t *testing.T) {
+		got, err := config.Load(filepath.Join(t.TempDir(), "config.toml"), false)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, got, config.Default())
+	})
+
+	t.Run("missing required file", func(t *testing.T) {
+		_, err := config.Load(filepath.Join(t.TempDir(), "config.toml"), true)
+		if err == nil {
+			t.Error("Expected an error but got none")
+		}
+	})
+
+	t.Run("partial file", func(t *testing.T) {
+		path := writeConfigFile(t, `day_length = "8h"`)
+
+		got, err := config.Load(path, true)
+		testutil.AssertNoErr(t, err)
+
+		want := config.Default()
+		want.DayLength = config.Duration(8 * time.Hour)
+		testutil.AssertEqualStructs(t, got, want)
+	})
+
+	t.Run("full file", func(t *testing.T) {
+		path := writeConfigFile(t, `
+day_length = "6h15m"
+time_format = "24h"
+date_format = "02.01.2006"
+week_start = "Sunday"
+output = "json"
+balance_start = "2023-01-01"
+
+[compliance]
+max_daily = "9h"
+break_after = "6h"
+min_break = "45m"
+min_rest = "0s"
+`)
+
+		got, err := config.Load(path, true)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, got, config.Config{
+			DayLength:    config.Duration(6*time.Hour + 15*time.Minute),
+			TimeFormat:   "24h",
+			DateFormat:   "02.01.2006",
+			WeekStart:    "Sunday",
+			Output:       "json",
+			BalanceStart: "2023-01-01",
+			Compliance: config.ComplianceConfig{
+				MaxDaily:   config.Duration(9 * time.Hour),
+				BreakAfter: config.Duration(6 * time.Hour),
+				MinBreak:   config.Duration(45 * time.Minute),
+			},
+		})
+
+		testutil.AssertEqualStructs(t, got.UtilSettings(), util.Settings{
+			DateFormat:  "02.01.2006",
+			Clock24Hour: true,
+			WeekStart:   time.Sunday,
+		})
+	})
+
+	invalidFiles := map[string]string{
+		"bad syntax":        `day_length = `,
+		"bad duration":      `day_length = "eight hours"`,
+		"negative duration": `day_length = "-1h"`,
+		"bad time format":   `time_format = "36h"`,
+		"empty date format": `date_format = ""`,
+		"bad week start":    `week_start = "someday"`,
+		"bad output":        `output = "xml"`,
+		"bad balance start": `balance_start = "last year"`,
+		"negative rule":     "[compliance]\nmin_rest = \"-1h\"",
+	}
+	for name, contents := range invalidFiles {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.Load(writeConfigFile(t, contents), true)
+			if err == nil {
+				t.Error("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Run("XDG_CONFIG_HOME set", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/config")
+
+		got, err := config.DefaultPath()
+		testutil.AssertNoErr(t, err)
+
+		if want := "/config/wh/config.toml"; got != want {
+			t.Errorf("got '%s', want '%s'", got, want)
+		}
+	})
+
+	t.Run("XDG_CONFIG_HOME unset", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("HOME", "/home/me")
+
+		got, err := config.DefaultPath()
+		testutil.AssertNoErr(t, err)
+
+		if want := "/home/me/.config/wh/config.toml"; got != want {
+			t.Errorf("got '%s', want '%s'", got, want)
+		}
+	})
+}
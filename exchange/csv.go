@@ -0,0 +1,85 @@
+// Package exchange reads and writes work data in formats shared with other
+// tools, such as CSV for spreadsheets and JSON for backups.
+package exchange
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+)
+
+// csvTimeFormat is how CSV exports write times, which spreadsheets can parse.
+const csvTimeFormat string = "2006-01-02 15:04"
+
+var csvPeriodHeader = []string{"date", "start", "end", "duration_mins", "break_mins", "note", "day_length_mins"}
+var csvDayHeader = []string{"date", "day_length_mins", "worked_mins", "break_mins", "balance_mins", "note"}
+
+// CSVWriter writes work days as CSV, either one row per work period or, when
+// perDay is set, one row per work day.
+type CSVWriter struct {
+	w      *csv.Writer
+	perDay bool
+}
+
+func NewCSVWriter(out io.Writer, perDay bool) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(out), perDay: perDay}
+}
+
+func (c *CSVWriter) WriteHeader() error {
+	if c.perDay {
+		return c.w.Write(csvDayHeader)
+	}
+
+	return c.w.Write(csvPeriodHeader)
+}
+
+// WriteWorkDay writes the work day's row, or a row for each of its work
+// periods. A work period's duration leaves out its breaks, so the durations
+// add up to the work day's time worked. Open work periods are written without
+// an end, duration or break time.
+func (c *CSVWriter) WriteWorkDay(workDay model.WorkDay) error {
+	date := workDay.Date.Format(util.DateFormatStr)
+	dayLength := formatMins(workDay.Length())
+
+	if c.perDay {
+		return c.w.Write([]string{
+			date,
+			dayLength,
+			formatMins(workDay.TimeWorked()),
+			formatMins(workDay.BreakTime()),
+			formatMins(workDay.Balance()),
+			workDay.Note.String,
+		})
+	}
+
+	for _, wp := range workDay.WorkPeriods() {
+		var end, duration, breakTime string
+		if wp.EndAt.Valid {
+			end = wp.EndAt.Time.Format(csvTimeFormat)
+			duration = formatMins(wp.TimeWorked() - workDay.BreakTimeDuring(wp))
+			breakTime = formatMins(workDay.BreakTimeDuring(wp))
+		}
+
+		row := []string{date, wp.StartAt.Format(csvTimeFormat), end, duration, breakTime, wp.Note.String, dayLength}
+		if err := c.w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush writes any buffered rows, returning the first error encountered while
+// writing.
+func (c *CSVWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatMins(d time.Duration) string {
+	return strconv.Itoa(int(d.Minutes()))
+}
@@ -0,0 +1,56 @@
+package exchange_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/exchange"
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func csvWorkDay() model.WorkDay {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	wp1 := model.WorkPeriod{StartAt: date.Add(9 * time.Hour)}
+	wp1.SetEndAt(date.Add(12*time.Hour + 30*time.Minute))
+	wp1.SetNote(`Billing "API", v2`)
+
+	wp2 := model.WorkPeriod{StartAt: date.Add(13 * time.Hour)}
+	wp2.SetEndAt(date.Add(17 * time.Hour))
+
+	b := model.Break{StartAt: date.Add(15 * time.Hour)}
+	b.SetEndAt(date.Add(15*time.Hour + 15*time.Minute))
+
+	wd := model.NewWorkDay(date)
+	wd.SetNote("Release day")
+	wd.SetWorkPeriods([]model.WorkPeriod{wp1, wp2})
+	wd.SetBreaks([]model.Break{b})
+	return wd
+}
+
+func TestCSVWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := exchange.NewCSVWriter(out, false)
+	testutil.AssertNoErr(t, w.WriteHeader())
+	testutil.AssertNoErr(t, w.WriteWorkDay(csvWorkDay()))
+	testutil.AssertNoErr(t, w.Flush())
+
+	testutil.AssertOutput(t, out, `date,start,end,duration_mins,break_mins,note,day_length_mins
+2023-09-01,2023-09-01 09:00,2023-09-01 12:30,210,0,"Billing ""API"", v2",450
+2023-09-01,2023-09-01 13:00,2023-09-01 17:00,225,15,,450
+`)
+}
+
+func TestCSVWriterPerDay(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := exchange.NewCSVWriter(out, true)
+	testutil.AssertNoErr(t, w.WriteHeader())
+	testutil.AssertNoErr(t, w.WriteWorkDay(csvWorkDay()))
+	testutil.AssertNoErr(t, w.Flush())
+
+	testutil.AssertOutput(t, out, `date,day_length_mins,worked_mins,break_mins,balance_mins,note
+2023-09-01,450,435,15,-15,Release day
+`)
+}
@@ -0,0 +1,135 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/robyparr/wh/model"
+)
+
+// icsTimeFormat is how iCalendar files write times in UTC. Writing every time
+// in UTC avoids having to describe the local time zone in a VTIMEZONE.
+const icsTimeFormat string = "20060102T150405Z"
+
+// icsLineLength is the longest a line may be, in bytes, before it's folded.
+const icsLineLength int = 75
+
+// ICSWriter writes closed work periods as iCalendar events. Each event's UID
+// is derived from the database's install ID and the work period's ID, so
+// calendars that import the file again update their events instead of
+// duplicating them, and events from different databases never collide.
+type ICSWriter struct {
+	w         *bufio.Writer
+	installId string
+}
+
+func NewICSWriter(out io.Writer, installId string) *ICSWriter {
+	return &ICSWriter{w: bufio.NewWriter(out), installId: installId}
+}
+
+func (i *ICSWriter) WriteHeader() error {
+	return i.writeLines(
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//wh//wh//EN",
+		"CALSCALE:GREGORIAN",
+	)
+}
+
+// WriteWorkDay writes an event for each of the work day's closed work
+// periods, with the note as the summary and the tags as categories.
+func (i *ICSWriter) WriteWorkDay(workDay model.WorkDay) error {
+	for _, wp := range workDay.WorkPeriods() {
+		if !wp.EndAt.Valid {
+			continue
+		}
+
+		summary := wp.Note.String
+		if summary == "" {
+			summary = "Work"
+		}
+
+		lines := []string{
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:work-period-%d-%s@wh", wp.Id, i.installId),
+			// The sequence has to grow with every change for some calendars to
+			// pick up updated events, which the update time does.
+			fmt.Sprintf("SEQUENCE:%d", wp.UpdatedAt.Unix()),
+			"DTSTAMP:" + wp.UpdatedAt.UTC().Format(icsTimeFormat),
+			"LAST-MODIFIED:" + wp.UpdatedAt.UTC().Format(icsTimeFormat),
+			"DTSTART:" + wp.StartAt.UTC().Format(icsTimeFormat),
+			"DTEND:" + wp.EndAt.Time.UTC().Format(icsTimeFormat),
+			"SUMMARY:" + escapeICSText(summary),
+		}
+
+		if len(wp.Tags) > 0 {
+			categories := make([]string, len(wp.Tags))
+			for j, tag := range wp.Tags {
+				categories[j] = escapeICSText(tag)
+			}
+
+			lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+		}
+
+		if err := i.writeLines(append(lines, "END:VEVENT")...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close ends the calendar and flushes it.
+func (i *ICSWriter) Close() error {
+	if err := i.writeLines("END:VCALENDAR"); err != nil {
+		return err
+	}
+
+	return i.w.Flush()
+}
+
+// writeLines writes each line folded and ended with CRLF, as iCalendar
+// requires.
+func (i *ICSWriter) writeLines(lines ...string) error {
+	for _, line := range lines {
+		if _, err := i.w.WriteString(foldICSLine(line) + "\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// foldICSLine splits lines longer than icsLineLength bytes, starting each
+// continuation with a space and never splitting a UTF-8 character.
+func foldICSLine(line string) string {
+	var folded strings.Builder
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		folded.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuations lose a byte to the leading space.
+		limit = icsLineLength - 1
+	}
+
+	folded.WriteString(line)
+	return folded.String()
+}
+
+func escapeICSText(str string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(str)
+}
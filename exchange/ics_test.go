@@ -0,0 +1,85 @@
+package exchange_test
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/exchange"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestICSWriter(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	testutil.AssertNoErr(t, err)
+
+	workDay := csvWorkDay()
+	periods := workDay.WorkPeriods()
+	periods[0].Id = 12
+	periods[0].StartAt = time.Date(2023, 9, 1, 9, 0, 0, 0, paris)
+	periods[0].SetEndAt(time.Date(2023, 9, 1, 12, 30, 0, 0, paris))
+	periods[0].UpdatedAt = time.Date(2023, 9, 1, 12, 31, 0, 0, paris)
+	periods[0].AddTags("billing", "api")
+	periods[1].Id = 13
+	periods[1].EndAt = sql.NullTime{}
+	workDay.SetWorkPeriods(periods)
+
+	out := &bytes.Buffer{}
+	w := exchange.NewICSWriter(out, "0123abcd")
+	testutil.AssertNoErr(t, w.WriteHeader())
+	testutil.AssertNoErr(t, w.WriteWorkDay(workDay))
+	testutil.AssertNoErr(t, w.Close())
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//wh//wh//EN",
+		"CALSCALE:GREGORIAN",
+		"BEGIN:VEVENT",
+		"UID:work-period-12-0123abcd@wh",
+		"SEQUENCE:1693564260",
+		"DTSTAMP:20230901T103100Z",
+		"LAST-MODIFIED:20230901T103100Z",
+		"DTSTART:20230901T070000Z",
+		"DTEND:20230901T103000Z",
+		`SUMMARY:Billing "API"\, v2`,
+		"CATEGORIES:api,billing",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+
+	testutil.AssertOutput(t, out, want)
+}
+
+func TestICSWriterFolding(t *testing.T) {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	workDay := csvWorkDay()
+	periods := workDay.WorkPeriods()[:1]
+	periods[0].StartAt = date.Add(9 * time.Hour)
+	periods[0].SetEndAt(date.Add(10 * time.Hour))
+	periods[0].SetNote("Réunion; " + strings.Repeat("é", 40) + "\nsuite")
+	workDay.SetWorkPeriods(periods)
+
+	out := &bytes.Buffer{}
+	w := exchange.NewICSWriter(out, "0123abcd")
+	testutil.AssertNoErr(t, w.WriteWorkDay(workDay))
+	testutil.AssertNoErr(t, w.Close())
+
+	var summary []string
+	for _, line := range strings.Split(out.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines of at most 75 bytes, got %d: %s", len(line), line)
+		}
+
+		if strings.HasPrefix(line, "SUMMARY:") || (len(summary) > 0 && strings.HasPrefix(line, " ")) {
+			summary = append(summary, strings.TrimPrefix(line, " "))
+		}
+	}
+
+	want := `SUMMARY:Réunion\; ` + strings.Repeat("é", 40) + `\nsuite`
+	if got := strings.Join(summary, ""); got != want {
+		t.Errorf("Expected unfolded summary '%s', got '%s'", want, got)
+	}
+}
@@ -0,0 +1,49 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+)
+
+// Rejection is a line or row of an imported file that was skipped. Readers
+// reject what they can't import rather than failing the whole file, so that
+// one bad entry doesn't hold back the rest.
+type Rejection struct {
+	Line   int
+	Reason string
+}
+
+func (r Rejection) String() string {
+	return fmt.Sprintf("line %d: %s", r.Line, r.Reason)
+}
+
+// groupWorkPeriods puts the work periods onto work days by the local date
+// they start on, in date order.
+func groupWorkPeriods(periods []model.WorkPeriod) []model.WorkDay {
+	byDate := make(map[time.Time][]model.WorkPeriod)
+	for _, period := range periods {
+		date := util.TimeAtMidnight(period.StartAt.Local())
+		byDate[date] = append(byDate[date], period)
+	}
+
+	workDays := make([]model.WorkDay, 0, len(byDate))
+	for date, datePeriods := range byDate {
+		sort.Slice(datePeriods, func(i, j int) bool {
+			return datePeriods[i].StartAt.Before(datePeriods[j].StartAt)
+		})
+
+		workDay := model.NewWorkDay(date)
+		workDay.SetWorkPeriods(datePeriods)
+		workDays = append(workDays, workDay)
+	}
+
+	sort.Slice(workDays, func(i, j int) bool {
+		return workDays[i].Date.Before(workDays[j].Date)
+	})
+
+	return workDays
+}
@@ -0,0 +1,327 @@
+package exchange
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util"
+)
+
+// JSONVersion is the version of the JSON backup format. It changes whenever a
+// backup written by this version couldn't be read by an older one.
+const JSONVersion int = 1
+
+// Backup holds everything read from a JSON backup. The install ID is the one
+// of the database it was exported from.
+type Backup struct {
+	Version    int
+	ExportedAt time.Time
+	InstallId  string
+	Projects   []model.Project
+	WorkDays   []model.WorkDay
+}
+
+type jsonBackup struct {
+	Version    int           `json:"version"`
+	ExportedAt time.Time     `json:"exported_at"`
+	InstallId  string        `json:"install_id"`
+	Projects   []jsonProject `json:"projects"`
+	WorkDays   []jsonWorkDay `json:"work_days"`
+}
+
+type jsonProject struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	ArchivedAt *time.Time `json:"archived_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type jsonWorkDay struct {
+	Id          int              `json:"id"`
+	Date        string           `json:"date"`
+	LengthMins  int              `json:"length_mins"`
+	Note        *string          `json:"note"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	WorkPeriods []jsonWorkPeriod `json:"work_periods"`
+	Breaks      []jsonBreak      `json:"breaks"`
+}
+
+type jsonWorkPeriod struct {
+	Id        int        `json:"id"`
+	StartAt   time.Time  `json:"start_at"`
+	EndAt     *time.Time `json:"end_at"`
+	Note      *string    `json:"note"`
+	ProjectId *int64     `json:"project_id"`
+	Tags      []string   `json:"tags"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type jsonBreak struct {
+	Id        int        `json:"id"`
+	StartAt   time.Time  `json:"start_at"`
+	EndAt     *time.Time `json:"end_at"`
+	Note      *string    `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// JSONWriter writes a JSON backup one work day at a time, so exports never
+// have to hold every work day in memory. The projects are written up front
+// since work periods refer to them by ID.
+type JSONWriter struct {
+	out      io.Writer
+	wroteDay bool
+}
+
+func NewJSONWriter(out io.Writer) *JSONWriter {
+	return &JSONWriter{out: out}
+}
+
+// WriteHeader writes the format version, export time, the database's install
+// ID and the projects, and opens the list of work days.
+func (j *JSONWriter) WriteHeader(exportedAt time.Time, installId string, projects []model.Project) error {
+	header := jsonBackup{
+		Version:    JSONVersion,
+		ExportedAt: exportedAt,
+		InstallId:  installId,
+		Projects:   make([]jsonProject, 0, len(projects)),
+	}
+	for _, p := range projects {
+		header.Projects = append(header.Projects, jsonProject{
+			Id:         p.Id,
+			Name:       p.Name,
+			ArchivedAt: fromNullTime(p.ArchivedAt),
+			CreatedAt:  p.CreatedAt,
+			UpdatedAt:  p.UpdatedAt,
+		})
+	}
+
+	projectsJSON, err := json.MarshalIndent(header.Projects, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	exportedAtJSON, err := json.Marshal(header.ExportedAt)
+	if err != nil {
+		return err
+	}
+
+	installIdJSON, err := json.Marshal(header.InstallId)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(j.out, "{\n  \"version\": %d,\n  \"exported_at\": %s,\n  \"install_id\": %s,\n  \"projects\": %s,\n  \"work_days\": [", header.Version, exportedAtJSON, installIdJSON, projectsJSON)
+	return err
+}
+
+func (j *JSONWriter) WriteWorkDay(workDay model.WorkDay) error {
+	dayJSON, err := json.MarshalIndent(toJSONWorkDay(workDay), "    ", "  ")
+	if err != nil {
+		return err
+	}
+
+	separator := ","
+	if !j.wroteDay {
+		separator = ""
+		j.wroteDay = true
+	}
+
+	_, err = fmt.Fprintf(j.out, "%s\n    %s", separator, dayJSON)
+	return err
+}
+
+// Close ends the list of work days and the backup.
+func (j *JSONWriter) Close() error {
+	end := "\n  ]\n}\n"
+	if !j.wroteDay {
+		end = "]\n}\n"
+	}
+
+	_, err := io.WriteString(j.out, end)
+	return err
+}
+
+// ReadJSON reads a JSON backup, rejecting versions it doesn't know.
+func ReadJSON(in io.Reader) (Backup, error) {
+	var backup jsonBackup
+	decoder := json.NewDecoder(in)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&backup); err != nil {
+		return Backup{}, fmt.Errorf("error reading JSON backup: %v", err)
+	}
+
+	if backup.Version != JSONVersion {
+		return Backup{}, fmt.Errorf("unsupported JSON backup version %d, expected %d", backup.Version, JSONVersion)
+	}
+
+	result := Backup{Version: backup.Version, ExportedAt: backup.ExportedAt, InstallId: backup.InstallId}
+	for _, p := range backup.Projects {
+		result.Projects = append(result.Projects, model.Project{
+			Id:         p.Id,
+			Name:       p.Name,
+			ArchivedAt: toNullTime(p.ArchivedAt),
+			CreatedAt:  p.CreatedAt,
+			UpdatedAt:  p.UpdatedAt,
+		})
+	}
+
+	for _, wd := range backup.WorkDays {
+		workDay, err := fromJSONWorkDay(wd)
+		if err != nil {
+			return Backup{}, err
+		}
+
+		result.WorkDays = append(result.WorkDays, workDay)
+	}
+
+	return result, nil
+}
+
+func toJSONWorkDay(workDay model.WorkDay) jsonWorkDay {
+	wd := jsonWorkDay{
+		Id:          workDay.Id,
+		Date:        workDay.Date.Format(util.DateFormatStr),
+		LengthMins:  workDay.LengthMins,
+		Note:        fromNullString(workDay.Note),
+		CreatedAt:   workDay.CreatedAt,
+		UpdatedAt:   workDay.UpdatedAt,
+		WorkPeriods: []jsonWorkPeriod{},
+		Breaks:      []jsonBreak{},
+	}
+
+	for _, p := range workDay.WorkPeriods() {
+		period := jsonWorkPeriod{
+			Id:        p.Id,
+			StartAt:   p.StartAt,
+			EndAt:     fromNullTime(p.EndAt),
+			Note:      fromNullString(p.Note),
+			Tags:      p.Tags,
+			CreatedAt: p.CreatedAt,
+			UpdatedAt: p.UpdatedAt,
+		}
+		if period.Tags == nil {
+			period.Tags = []string{}
+		}
+		if p.ProjectId.Valid {
+			id := p.ProjectId.Int64
+			period.ProjectId = &id
+		}
+
+		wd.WorkPeriods = append(wd.WorkPeriods, period)
+	}
+
+	for _, b := range workDay.Breaks() {
+		wd.Breaks = append(wd.Breaks, jsonBreak{
+			Id:        b.Id,
+			StartAt:   b.StartAt,
+			EndAt:     fromNullTime(b.EndAt),
+			Note:      fromNullString(b.Note),
+			CreatedAt: b.CreatedAt,
+			UpdatedAt: b.UpdatedAt,
+		})
+	}
+
+	return wd
+}
+
+func fromJSONWorkDay(wd jsonWorkDay) (model.WorkDay, error) {
+	date, err := time.ParseInLocation(util.DateFormatStr, wd.Date, time.Local)
+	if err != nil {
+		return model.WorkDay{}, fmt.Errorf("invalid work day date '%s': %v", wd.Date, err)
+	}
+
+	workDay := model.WorkDay{
+		Id:         wd.Id,
+		Date:       date,
+		LengthMins: wd.LengthMins,
+		Note:       toNullString(wd.Note),
+		CreatedAt:  wd.CreatedAt,
+		UpdatedAt:  wd.UpdatedAt,
+	}
+
+	var periods []model.WorkPeriod
+	for _, p := range wd.WorkPeriods {
+		period := model.WorkPeriod{
+			Id:        p.Id,
+			WorkDayId: wd.Id,
+			StartAt:   p.StartAt,
+			EndAt:     toNullTime(p.EndAt),
+			Note:      toNullString(p.Note),
+			CreatedAt: p.CreatedAt,
+			UpdatedAt: p.UpdatedAt,
+		}
+		period.AddTags(p.Tags...)
+		if p.ProjectId != nil {
+			period.ProjectId = sql.NullInt64{Valid: true, Int64: *p.ProjectId}
+		}
+
+		if err := period.Validate(); err != nil {
+			return model.WorkDay{}, fmt.Errorf("invalid work period #%d on %s: %v", p.Id, wd.Date, err)
+		}
+
+		periods = append(periods, period)
+	}
+
+	var breaks []model.Break
+	for _, b := range wd.Breaks {
+		brk := model.Break{
+			Id:        b.Id,
+			WorkDayId: wd.Id,
+			StartAt:   b.StartAt,
+			EndAt:     toNullTime(b.EndAt),
+			Note:      toNullString(b.Note),
+			CreatedAt: b.CreatedAt,
+			UpdatedAt: b.UpdatedAt,
+		}
+
+		if err := brk.Validate(); err != nil {
+			return model.WorkDay{}, fmt.Errorf("invalid break #%d on %s: %v", b.Id, wd.Date, err)
+		}
+
+		breaks = append(breaks, brk)
+	}
+
+	workDay.SetWorkPeriods(periods)
+	workDay.SetBreaks(breaks)
+	return workDay, nil
+}
+
+func fromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Valid: true, Time: *t}
+}
+
+func fromNullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+
+	return &s.String
+}
+
+func toNullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{Valid: true, String: *s}
+}
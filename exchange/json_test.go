@@ -0,0 +1,118 @@
+package exchange_test
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/exchange"
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	exportedAt := time.Date(2023, 9, 2, 18, 0, 0, 0, time.Local)
+	projects := []model.Project{{Id: 3, Name: "acme", CreatedAt: exportedAt, UpdatedAt: exportedAt}}
+
+	workDay := csvWorkDay()
+	workDay.Id = 7
+	periods := workDay.WorkPeriods()
+	periods[0].Id = 11
+	periods[0].WorkDayId = 7
+	periods[1].WorkDayId = 7
+	periods[0].ProjectId = sql.NullInt64{Valid: true, Int64: 3}
+	periods[0].AddTags("billing")
+	workDay.SetWorkPeriods(periods)
+	breaks := workDay.Breaks()
+	breaks[0].WorkDayId = 7
+	workDay.SetBreaks(breaks)
+
+	out := &bytes.Buffer{}
+	w := exchange.NewJSONWriter(out)
+	testutil.AssertNoErr(t, w.WriteHeader(exportedAt, "0123abcd", projects))
+	testutil.AssertNoErr(t, w.WriteWorkDay(workDay))
+	testutil.AssertNoErr(t, w.WriteWorkDay(model.NewWorkDay(workDay.Date.AddDate(0, 0, 1))))
+	testutil.AssertNoErr(t, w.Close())
+
+	got, err := exchange.ReadJSON(out)
+	testutil.AssertNoErr(t, err)
+
+	if got.Version != exchange.JSONVersion {
+		t.Errorf("Expected version %d, got %d", exchange.JSONVersion, got.Version)
+	}
+	testutil.AssertAroundTime(t, "ExportedAt", got.ExportedAt, exportedAt)
+	if got.InstallId != "0123abcd" {
+		t.Errorf("Expected install ID '0123abcd', got '%s'", got.InstallId)
+	}
+
+	if len(got.Projects) != 1 {
+		t.Fatalf("Expected 1 project, got %d", len(got.Projects))
+	}
+	testutil.AssertEqualStructs(t, got.Projects[0], projects[0])
+
+	if len(got.WorkDays) != 2 {
+		t.Fatalf("Expected 2 work days, got %d", len(got.WorkDays))
+	}
+	testutil.AssertEqualStructs(t, got.WorkDays[0], workDay)
+
+	gotPeriods := got.WorkDays[0].WorkPeriods()
+	if len(gotPeriods) != 2 {
+		t.Fatalf("Expected 2 work periods, got %d", len(gotPeriods))
+	}
+	for i := range periods {
+		gotTags, wantTags := gotPeriods[i].Tags, periods[i].Tags
+		gotPeriods[i].Tags, periods[i].Tags = nil, nil
+		testutil.AssertEqualStructs(t, gotPeriods[i], periods[i])
+
+		if strings.Join(gotTags, ",") != strings.Join(wantTags, ",") {
+			t.Errorf("Expected tags %v, got %v", wantTags, gotTags)
+		}
+	}
+
+	gotBreaks := got.WorkDays[0].Breaks()
+	if len(gotBreaks) != 1 {
+		t.Fatalf("Expected 1 break, got %d", len(gotBreaks))
+	}
+	testutil.AssertEqualStructs(t, gotBreaks[0], workDay.Breaks()[0])
+}
+
+func TestJSONWriterEmpty(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := exchange.NewJSONWriter(out)
+	testutil.AssertNoErr(t, w.WriteHeader(time.Date(2023, 9, 2, 18, 0, 0, 0, time.UTC), "0123abcd", nil))
+	testutil.AssertNoErr(t, w.Close())
+
+	testutil.AssertOutput(t, out, `{
+  "version": 1,
+  "exported_at": "2023-09-02T18:00:00Z",
+  "install_id": "0123abcd",
+  "projects": [],
+  "work_days": []
+}
+`)
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"malformed", `{"version": 1,`},
+		{"unknown version", `{"version": 2, "projects": [], "work_days": []}`},
+		{"invalid date", `{"version": 1, "work_days": [{"date": "Sept 1"}]}`},
+		{"period ends before it starts", `{"version": 1, "work_days": [{"date": "2023-09-01", "work_periods": [
+			{"start_at": "2023-09-01T10:00:00Z", "end_at": "2023-09-01T09:00:00Z"}
+		]}]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := exchange.ReadJSON(strings.NewReader(test.input))
+			if err == nil {
+				t.Error("Expected an error but got none")
+			}
+		})
+	}
+}
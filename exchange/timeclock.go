@@ -0,0 +1,188 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/model"
+)
+
+// timeclockTimeFormat is how timeclock files write clock-in and clock-out
+// times, in local time.
+const timeclockTimeFormat string = "2006/01/02 15:04:05"
+
+// timeclockAccount is the account work periods without a project are written
+// to.
+const timeclockAccount string = "work"
+
+var timeclockTimeFormats = []string{
+	timeclockTimeFormat,
+	"2006/01/02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// TimeclockWriter writes work periods as a timeclock file, as used by Emacs
+// and ledger, that hledger can report on. Work periods are written to their
+// project's account and their note is written as the description.
+type TimeclockWriter struct {
+	w        *bufio.Writer
+	projects map[int64]string
+}
+
+func NewTimeclockWriter(out io.Writer, projects []model.Project) *TimeclockWriter {
+	names := make(map[int64]string)
+	for _, p := range projects {
+		names[int64(p.Id)] = p.Name
+	}
+
+	return &TimeclockWriter{w: bufio.NewWriter(out), projects: names}
+}
+
+// WriteWorkDay writes a clock-in and a clock-out line for each of the work
+// day's work periods. Open work periods are only clocked in.
+func (t *TimeclockWriter) WriteWorkDay(workDay model.WorkDay) error {
+	for _, wp := range workDay.WorkPeriods() {
+		account := timeclockAccount
+		if name, ok := t.projects[wp.ProjectId.Int64]; wp.ProjectId.Valid && ok {
+			account = name
+		}
+
+		line := fmt.Sprintf("i %s %s", wp.StartAt.Local().Format(timeclockTimeFormat), account)
+		if note := singleLine(wp.Note.String); note != "" {
+			line += "  " + note
+		}
+
+		if _, err := fmt.Fprintln(t.w, line); err != nil {
+			return err
+		}
+
+		if wp.EndAt.Valid {
+			if _, err := fmt.Fprintf(t.w, "o %s\n", wp.EndAt.Time.Local().Format(timeclockTimeFormat)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *TimeclockWriter) Flush() error {
+	return t.w.Flush()
+}
+
+// ReadTimeclock reads clock-in and clock-out pairs from a timeclock file into
+// work days. The account and description of the clock-in become the work
+// period's note. Unmatched clock-ins and clock-outs are rejected.
+func ReadTimeclock(in io.Reader) ([]model.WorkDay, []Rejection, error) {
+	var periods []model.WorkPeriod
+	var rejections []Rejection
+
+	var clockIn *model.WorkPeriod
+	var clockInLine int
+
+	scanner := bufio.NewScanner(in)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.ContainsAny(line[:1], ";#*") {
+			continue
+		}
+
+		code, rest, _ := strings.Cut(line, " ")
+		switch code {
+		case "i":
+			if clockIn != nil {
+				rejections = append(rejections, Rejection{clockInLine, "clock-in without a clock-out"})
+				clockIn = nil
+			}
+
+			at, text, err := parseTimeclockTime(rest)
+			if err != nil {
+				rejections = append(rejections, Rejection{lineNum, err.Error()})
+				continue
+			}
+
+			account, description, _ := cutTimeclockDescription(text)
+			var note string
+			switch {
+			case account != "" && description != "":
+				note = account + ": " + description
+			default:
+				note = account + description
+			}
+
+			period := model.WorkPeriod{StartAt: at}
+			period.SetNote(note)
+			clockIn, clockInLine = &period, lineNum
+		case "o", "O":
+			if clockIn == nil {
+				rejections = append(rejections, Rejection{lineNum, "clock-out without a clock-in"})
+				continue
+			}
+
+			at, _, err := parseTimeclockTime(rest)
+			if err != nil {
+				rejections = append(rejections, Rejection{lineNum, err.Error()})
+				continue
+			}
+
+			clockIn.SetEndAt(at)
+			if err := clockIn.Validate(); err != nil {
+				rejections = append(rejections, Rejection{lineNum, err.Error()})
+			} else {
+				periods = append(periods, *clockIn)
+			}
+
+			clockIn = nil
+		default:
+			rejections = append(rejections, Rejection{lineNum, fmt.Sprintf("unsupported entry '%s'", code)})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error reading timeclock file: %v", err)
+	}
+
+	if clockIn != nil {
+		rejections = append(rejections, Rejection{clockInLine, "clock-in without a clock-out"})
+	}
+
+	return groupWorkPeriods(periods), rejections, nil
+}
+
+// parseTimeclockTime parses the date and time at the start of str, returning
+// the rest of it.
+func parseTimeclockTime(str string) (time.Time, string, error) {
+	fields := strings.SplitN(strings.TrimSpace(str), " ", 3)
+	if len(fields) < 2 {
+		return time.Time{}, "", fmt.Errorf("expected a date and time, got '%s'", str)
+	}
+
+	dateTime := fields[0] + " " + fields[1]
+	for _, layout := range timeclockTimeFormats {
+		if t, err := time.ParseInLocation(layout, dateTime, time.Local); err == nil {
+			rest := ""
+			if len(fields) == 3 {
+				rest = strings.TrimSpace(fields[2])
+			}
+
+			return t, rest, nil
+		}
+	}
+
+	return time.Time{}, "", fmt.Errorf("invalid date and time '%s'", dateTime)
+}
+
+// cutTimeclockDescription splits a clock-in's account from its description,
+// which are separated by two spaces or a tab.
+func cutTimeclockDescription(str string) (string, string, bool) {
+	account, description, found := strings.Cut(strings.ReplaceAll(str, "\t", "  "), "  ")
+	return strings.TrimSpace(account), strings.TrimSpace(description), found
+}
+
+func singleLine(str string) string {
+	return strings.Join(strings.Fields(str), " ")
+}
@@ -0,0 +1,96 @@
+package exchange_test
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/exchange"
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestTimeclockWriter(t *testing.T) {
+	workDay := csvWorkDay()
+	periods := workDay.WorkPeriods()
+	periods[0].ProjectId = sql.NullInt64{Valid: true, Int64: 3}
+	periods[1].EndAt = sql.NullTime{}
+	workDay.SetWorkPeriods(periods)
+
+	out := &bytes.Buffer{}
+	w := exchange.NewTimeclockWriter(out, []model.Project{{Id: 3, Name: "acme"}})
+	testutil.AssertNoErr(t, w.WriteWorkDay(workDay))
+	testutil.AssertNoErr(t, w.Flush())
+
+	testutil.AssertOutput(t, out, `i 2023/09/01 09:00:00 acme  Billing "API", v2
+o 2023/09/01 12:30:00
+i 2023/09/01 13:00:00 work
+`)
+}
+
+func TestReadTimeclock(t *testing.T) {
+	input := `; Imported from Emacs
+i 2023/09/01 09:00:00 acme:billing  Invoices
+o 2023/09/01 12:30:00
+
+i 2023/09/01 13:00 internal
+o 2023/09/01 17:00
+o 2023/09/01 17:05:00
+i 2023/09/02 25:00:00 acme
+i 2023-09-03 22:00:00 support	On call
+o 2023-09-04 01:00:00
+i 2023/09/05 09:00:00 acme
+o 2023/09/05 08:00:00
+h 2023/09/05 09:00:00 acme
+i 2023/09/06 09:00:00 acme
+`
+
+	workDays, rejections, err := exchange.ReadTimeclock(strings.NewReader(input))
+	testutil.AssertNoErr(t, err)
+
+	wantRejections := []string{
+		"line 7: clock-out without a clock-in",
+		"line 8: invalid date and time '2023/09/02 25:00:00'",
+		"line 12: work period must not end before it starts",
+		"line 13: unsupported entry 'h'",
+		"line 14: clock-in without a clock-out",
+	}
+	if len(rejections) != len(wantRejections) {
+		t.Fatalf("Expected %d rejections, got %v", len(wantRejections), rejections)
+	}
+	for i, want := range wantRejections {
+		if got := rejections[i].String(); got != want {
+			t.Errorf("Expected rejection '%s', got '%s'", want, got)
+		}
+	}
+
+	if len(workDays) != 2 {
+		t.Fatalf("Expected 2 work days, got %d", len(workDays))
+	}
+
+	sep1 := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	testutil.AssertAroundTime(t, "Date", workDays[0].Date, sep1)
+
+	periods := workDays[0].WorkPeriods()
+	wantNotes := []string{"acme:billing: Invoices", "internal"}
+	if len(periods) != 2 {
+		t.Fatalf("Expected 2 work periods, got %d", len(periods))
+	}
+	for i, want := range wantNotes {
+		if periods[i].Note.String != want {
+			t.Errorf("Expected note '%s', got '%s'", want, periods[i].Note.String)
+		}
+	}
+	testutil.AssertAroundTime(t, "EndAt", periods[0].EndAt.Time, sep1.Add(12*time.Hour+30*time.Minute))
+
+	// Work periods past midnight belong to the day they started on.
+	testutil.AssertAroundTime(t, "Date", workDays[1].Date, time.Date(2023, 9, 3, 0, 0, 0, 0, time.Local))
+	if got := workDays[1].TimeWorked(); got != 3*time.Hour {
+		t.Errorf("Expected 3h worked, got %s", got)
+	}
+	if got := workDays[1].WorkPeriods()[0].Note.String; got != "support: On call" {
+		t.Errorf("Expected note 'support: On call', got '%s'", got)
+	}
+}
@@ -0,0 +1,263 @@
+package exchange
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/model"
+)
+
+// timesheetLayout maps the columns of another tool's CSV export, by header
+// name, onto work periods. Tools that only export hours leave the time
+// columns empty.
+type timesheetLayout struct {
+	startDate   string
+	startTime   string
+	endDate     string
+	endTime     string
+	hours       string
+	project     string
+	description string
+	tags        string
+}
+
+var timesheetLayouts = map[string]timesheetLayout{
+	"toggl": {
+		startDate:   "Start date",
+		startTime:   "Start time",
+		endDate:     "End date",
+		endTime:     "End time",
+		project:     "Project",
+		description: "Description",
+		tags:        "Tags",
+	},
+	"clockify": {
+		startDate:   "Start Date",
+		startTime:   "Start Time",
+		endDate:     "End Date",
+		endTime:     "End Time",
+		project:     "Project",
+		description: "Description",
+		tags:        "Tags",
+	},
+	"harvest": {
+		startDate:   "Date",
+		hours:       "Hours",
+		project:     "Project",
+		description: "Notes",
+	},
+}
+
+// TimesheetFormats lists the tools whose CSV exports ReadTimesheetCSV reads.
+var TimesheetFormats = []string{"toggl", "clockify", "harvest"}
+
+// harvestDayStart is when the first entry of a day starts for tools that
+// only export hours. Later entries follow on from the one before.
+const harvestDayStart = 9 * time.Hour
+
+// Slashed dates are read month first, as the tools write them by default.
+var timesheetDateFormats = []string{"2006-01-02", "01/02/2006", "02.01.2006"}
+var timesheetTimeFormats = []string{"15:04:05", "15:04", "03:04:05 PM", "3:04:05 PM", "03:04 PM", "3:04 PM"}
+
+// ReadTimesheetCSV reads a Toggl, Clockify or Harvest CSV export into work
+// days, one work period per row. Each row's project and description become
+// the note, and its tags become tags. Rows with invalid dates, times or
+// hours are rejected.
+func ReadTimesheetCSV(in io.Reader, format string) ([]model.WorkDay, []Rejection, error) {
+	layout, ok := timesheetLayouts[format]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown CSV format '%s', expected one of %s", format, strings.Join(TimesheetFormats, ", "))
+	}
+
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading %s CSV header: %v", format, err)
+	}
+
+	columns, err := layout.columns(header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a %s CSV export: %v", format, err)
+	}
+
+	var periods []model.WorkPeriod
+	var rejections []Rejection
+	nextStart := make(map[time.Time]time.Time)
+
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("error reading %s CSV: %v", format, err)
+			}
+
+			rejections = append(rejections, Rejection{parseErr.StartLine, parseErr.Err.Error()})
+			continue
+		}
+
+		line, _ := r.FieldPos(0)
+		period, err := columns.workPeriod(row, nextStart)
+		if err != nil {
+			rejections = append(rejections, Rejection{line, err.Error()})
+			continue
+		}
+
+		periods = append(periods, period)
+	}
+
+	return groupWorkPeriods(periods), rejections, nil
+}
+
+// timesheetColumns holds the index of each of a layout's columns in a CSV
+// header, or -1 for the columns the layout doesn't have.
+type timesheetColumns struct {
+	startDate, startTime, endDate, endTime, hours int
+	project, description, tags                    int
+}
+
+func (l timesheetLayout) columns(header []string) (timesheetColumns, error) {
+	indexes := make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		indexes[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var missing []string
+	index := func(name string, required bool) int {
+		if name == "" {
+			return -1
+		}
+
+		i, ok := indexes[strings.ToLower(name)]
+		if !ok {
+			if required {
+				missing = append(missing, name)
+			}
+
+			return -1
+		}
+
+		return i
+	}
+
+	hasTimes := l.hours == ""
+	columns := timesheetColumns{
+		startDate:   index(l.startDate, true),
+		startTime:   index(l.startTime, hasTimes),
+		endDate:     index(l.endDate, hasTimes),
+		endTime:     index(l.endTime, hasTimes),
+		hours:       index(l.hours, !hasTimes),
+		project:     index(l.project, false),
+		description: index(l.description, false),
+		tags:        index(l.tags, false),
+	}
+
+	if len(missing) > 0 {
+		return timesheetColumns{}, fmt.Errorf("missing columns %s", strings.Join(missing, ", "))
+	}
+
+	return columns, nil
+}
+
+// workPeriod converts a row into a work period. nextStart tracks when the
+// next entry of each date starts for rows that only have hours.
+func (c timesheetColumns) workPeriod(row []string, nextStart map[time.Time]time.Time) (model.WorkPeriod, error) {
+	field := func(i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+
+		return strings.TrimSpace(row[i])
+	}
+
+	var period model.WorkPeriod
+	if c.hours >= 0 {
+		date, err := parseTimesheetDate(field(c.startDate))
+		if err != nil {
+			return model.WorkPeriod{}, err
+		}
+
+		// Entries that round to no time at all would be empty work periods.
+		hours, err := strconv.ParseFloat(field(c.hours), 64)
+		duration := time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+		if err != nil || hours <= 0 || duration <= 0 {
+			return model.WorkPeriod{}, fmt.Errorf("invalid hours '%s'", field(c.hours))
+		}
+
+		start, ok := nextStart[date]
+		if !ok {
+			start = date.Add(harvestDayStart)
+		}
+
+		period.StartAt = start
+		period.SetEndAt(start.Add(duration))
+		nextStart[date] = period.EndAt.Time
+	} else {
+		start, err := parseTimesheetDateTime(field(c.startDate), field(c.startTime))
+		if err != nil {
+			return model.WorkPeriod{}, fmt.Errorf("invalid start: %v", err)
+		}
+
+		end, err := parseTimesheetDateTime(field(c.endDate), field(c.endTime))
+		if err != nil {
+			return model.WorkPeriod{}, fmt.Errorf("invalid end: %v", err)
+		}
+
+		period.StartAt = start
+		period.SetEndAt(end)
+	}
+
+	if err := period.Validate(); err != nil {
+		return model.WorkPeriod{}, err
+	}
+
+	project, description := field(c.project), field(c.description)
+	if project != "" && description != "" {
+		period.SetNote(project + ": " + description)
+	} else {
+		period.SetNote(project + description)
+	}
+
+	if tags := field(c.tags); tags != "" {
+		period.AddTags(strings.Split(tags, ",")...)
+	}
+
+	return period, nil
+}
+
+func parseTimesheetDate(str string) (time.Time, error) {
+	for _, layout := range timesheetDateFormats {
+		if date, err := time.ParseInLocation(layout, str, time.Local); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date '%s'", str)
+}
+
+func parseTimesheetDateTime(dateStr string, timeStr string) (time.Time, error) {
+	date, err := parseTimesheetDate(dateStr)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, layout := range timesheetTimeFormats {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(timeStr), time.Local); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time '%s'", timeStr)
+}
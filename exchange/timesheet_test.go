@@ -0,0 +1,131 @@
+package exchange_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/exchange"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestReadTimesheetCSV(t *testing.T) {
+	sep1 := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	type wantPeriod struct {
+		start time.Time
+		end   time.Time
+		note  string
+		tags  string
+	}
+
+	tests := []struct {
+		format         string
+		input          string
+		wantPeriods    []wantPeriod
+		wantRejections []string
+	}{
+		{
+			format: "toggl",
+			input: "\ufeffUser,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()\n" +
+				"Sam,sam@example.com,Acme,Website,,Fix login,Yes,2023-09-01,09:00:00,2023-09-01,11:30:00,02:30:00,\"billing, Support\",\n" +
+				"Sam,sam@example.com,,,,Standup,No,2023-09-01,11:30:00,2023-09-01,11:45:00,00:15:00,,\n" +
+				"Sam,sam@example.com,,,,Late,No,2023-09-01,11:00:00,2023-09-01,10:00:00,00:00:00,,\n" +
+				"Sam,sam@example.com,,,,Typo,No,2023-09-01,11am,2023-09-01,12:00:00,00:00:00,,\n",
+			wantPeriods: []wantPeriod{
+				{sep1.Add(9 * time.Hour), sep1.Add(11*time.Hour + 30*time.Minute), "Website: Fix login", "billing,support"},
+				{sep1.Add(11*time.Hour + 30*time.Minute), sep1.Add(11*time.Hour + 45*time.Minute), "Standup", ""},
+			},
+			wantRejections: []string{
+				"line 4: work period must not end before it starts",
+				"line 5: invalid start: invalid time '11am'",
+			},
+		},
+		{
+			format: "clockify",
+			input: `"Project","Client","Description","Task","User","Group","Email","Tags","Billable","Start Date","Start Time","End Date","End Time","Duration (h)","Duration (decimal)"
+"Website","Acme","Fix login","","Sam","","sam@example.com","","Yes","09/01/2023","01:00:00 PM","09/01/2023","03:15:00 PM","02:15:00","2.25"
+"Website","Acme","","","Sam","","sam@example.com","","Yes","13/01/2023","01:00:00 PM","13/01/2023","02:00:00 PM","01:00:00","1.00"
+`,
+			wantPeriods: []wantPeriod{
+				{sep1.Add(13 * time.Hour), sep1.Add(15*time.Hour + 15*time.Minute), "Website: Fix login", ""},
+			},
+			wantRejections: []string{"line 3: invalid start: invalid date '13/01/2023'"},
+		},
+		{
+			format: "harvest",
+			input: `Date,Client,Project,Project Code,Task,Notes,Hours,Hours Rounded,Billable?
+2023-09-01,Acme,Website,,Development,Fix login,2.5,2.5,Yes
+2023-09-01,Acme,Website,,Meetings,,0.25,0.25,No
+2023-09-01,Acme,Website,,Meetings,,lots,0.25,No
+2023-09-01,Acme,Website,,Meetings,,0,0,No
+2023-09-01,Acme,Website,,Meetings,,0.001,0,No
+`,
+			wantPeriods: []wantPeriod{
+				{sep1.Add(9 * time.Hour), sep1.Add(11*time.Hour + 30*time.Minute), "Website: Fix login", ""},
+				{sep1.Add(11*time.Hour + 30*time.Minute), sep1.Add(11*time.Hour + 45*time.Minute), "Website", ""},
+			},
+			wantRejections: []string{"line 4: invalid hours 'lots'", "line 5: invalid hours '0'", "line 6: invalid hours '0.001'"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			workDays, rejections, err := exchange.ReadTimesheetCSV(strings.NewReader(test.input), test.format)
+			testutil.AssertNoErr(t, err)
+
+			if len(rejections) != len(test.wantRejections) {
+				t.Fatalf("Expected %d rejections, got %v", len(test.wantRejections), rejections)
+			}
+			for i, want := range test.wantRejections {
+				if got := rejections[i].String(); got != want {
+					t.Errorf("Expected rejection '%s', got '%s'", want, got)
+				}
+			}
+
+			if len(workDays) != 1 {
+				t.Fatalf("Expected 1 work day, got %d", len(workDays))
+			}
+			testutil.AssertAroundTime(t, "Date", workDays[0].Date, sep1)
+
+			periods := workDays[0].WorkPeriods()
+			if len(periods) != len(test.wantPeriods) {
+				t.Fatalf("Expected %d work periods, got %d", len(test.wantPeriods), len(periods))
+			}
+
+			for i, want := range test.wantPeriods {
+				got := periods[i]
+				testutil.AssertAroundTime(t, "StartAt", got.StartAt, want.start)
+				testutil.AssertAroundTime(t, "EndAt", got.EndAt.Time, want.end)
+
+				if got.Note.String != want.note {
+					t.Errorf("Expected note '%s', got '%s'", want.note, got.Note.String)
+				}
+				if tags := strings.Join(got.Tags, ","); tags != want.tags {
+					t.Errorf("Expected tags '%s', got '%s'", want.tags, tags)
+				}
+			}
+		})
+	}
+}
+
+func TestReadTimesheetCSVErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{"unknown format", "freshbooks", "Date,Hours\n"},
+		{"empty file", "toggl", ""},
+		{"missing columns", "harvest", "Date,Client,Project\n2023-09-01,Acme,Website\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := exchange.ReadTimesheetCSV(strings.NewReader(test.input), test.format)
+			if err == nil {
+				t.Error("Expected an error but got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/robyparr/wh/model"
+)
+
+// timewarriorTimeFormat is how Timewarrior data files write times, in UTC.
+const timewarriorTimeFormat string = "20060102T150405Z"
+
+// TimewarriorWriter writes work periods in the format of Timewarrior's data
+// files. The project and tags of a work period become Timewarrior tags and
+// the note becomes its annotation.
+type TimewarriorWriter struct {
+	w        *bufio.Writer
+	projects map[int64]string
+}
+
+func NewTimewarriorWriter(out io.Writer, projects []model.Project) *TimewarriorWriter {
+	names := make(map[int64]string)
+	for _, p := range projects {
+		names[int64(p.Id)] = p.Name
+	}
+
+	return &TimewarriorWriter{w: bufio.NewWriter(out), projects: names}
+}
+
+// WriteWorkDay writes an interval for each of the work day's work periods.
+// Open work periods are written without an end, as Timewarrior does.
+func (t *TimewarriorWriter) WriteWorkDay(workDay model.WorkDay) error {
+	for _, wp := range workDay.WorkPeriods() {
+		line := "inc " + wp.StartAt.UTC().Format(timewarriorTimeFormat)
+		if wp.EndAt.Valid {
+			line += " - " + wp.EndAt.Time.UTC().Format(timewarriorTimeFormat)
+		}
+
+		var tags []string
+		if name, ok := t.projects[wp.ProjectId.Int64]; wp.ProjectId.Valid && ok {
+			tags = append(tags, name)
+		}
+		tags = append(tags, wp.Tags...)
+
+		note := singleLine(wp.Note.String)
+		if len(tags) > 0 || note != "" {
+			line += " #"
+		}
+
+		for _, tag := range tags {
+			line += " " + quoteTimewarriorWord(tag, false)
+		}
+
+		if note != "" {
+			line += " # " + quoteTimewarriorWord(note, true)
+		}
+
+		if _, err := fmt.Fprintln(t.w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *TimewarriorWriter) Flush() error {
+	return t.w.Flush()
+}
+
+// ReadTimewarrior reads the intervals of Timewarrior data files into work
+// days. An interval's annotation and tags become the work period's note.
+// Open intervals are rejected.
+func ReadTimewarrior(in io.Reader) ([]model.WorkDay, []Rejection, error) {
+	var periods []model.WorkPeriod
+	var rejections []Rejection
+
+	scanner := bufio.NewScanner(in)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		period, err := parseTimewarriorInterval(line)
+		if err != nil {
+			rejections = append(rejections, Rejection{lineNum, err.Error()})
+			continue
+		}
+
+		periods = append(periods, period)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error reading Timewarrior data: %v", err)
+	}
+
+	return groupWorkPeriods(periods), rejections, nil
+}
+
+func parseTimewarriorInterval(line string) (model.WorkPeriod, error) {
+	interval, words, _ := strings.Cut(line, "#")
+
+	fields := strings.Fields(interval)
+	if len(fields) == 0 || fields[0] != "inc" {
+		return model.WorkPeriod{}, fmt.Errorf("expected an 'inc' interval, got '%s'", line)
+	}
+
+	var period model.WorkPeriod
+	switch {
+	case len(fields) == 2:
+		return model.WorkPeriod{}, fmt.Errorf("interval is still open")
+	case len(fields) == 4 && fields[2] == "-":
+		start, err := time.Parse(timewarriorTimeFormat, fields[1])
+		if err != nil {
+			return model.WorkPeriod{}, fmt.Errorf("invalid start '%s'", fields[1])
+		}
+
+		end, err := time.Parse(timewarriorTimeFormat, fields[3])
+		if err != nil {
+			return model.WorkPeriod{}, fmt.Errorf("invalid end '%s'", fields[3])
+		}
+
+		period.StartAt = start.Local()
+		period.SetEndAt(end.Local())
+	default:
+		return model.WorkPeriod{}, fmt.Errorf("expected 'inc <start> - <end>', got '%s'", strings.TrimSpace(interval))
+	}
+
+	if err := period.Validate(); err != nil {
+		return model.WorkPeriod{}, err
+	}
+
+	tags, err := splitTimewarriorWords(words)
+	if err != nil {
+		return model.WorkPeriod{}, err
+	}
+
+	// Everything after a second '#' is the annotation, which leads the note.
+	for i, tag := range tags {
+		if tag == "#" {
+			tags = append(tags[i+1:], tags[:i]...)
+			break
+		}
+	}
+
+	period.SetNote(strings.Join(tags, ", "))
+	return period, nil
+}
+
+// splitTimewarriorWords splits the tags and annotation of an interval on
+// spaces, keeping double-quoted words together.
+func splitTimewarriorWords(str string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord, quoted, escaped := false, false, false
+
+	for _, r := range str {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			inWord = true
+		case r == ' ' && !quoted:
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in '%s'", strings.TrimSpace(str))
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// quoteTimewarriorWord quotes words that would otherwise be split or read as
+// something else. Annotations are always quoted.
+func quoteTimewarriorWord(word string, always bool) string {
+	if !always && word != "" && !strings.ContainsAny(word, " \"#\\") {
+		return word
+	}
+
+	word = strings.ReplaceAll(word, `\`, `\\`)
+	return `"` + strings.ReplaceAll(word, `"`, `\"`) + `"`
+}
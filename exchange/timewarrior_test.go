@@ -0,0 +1,84 @@
+package exchange_test
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/exchange"
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestTimewarriorWriter(t *testing.T) {
+	workDay := csvWorkDay()
+	periods := workDay.WorkPeriods()
+	periods[0].ProjectId = sql.NullInt64{Valid: true, Int64: 3}
+	periods[0].AddTags("billing")
+	periods[1].EndAt = sql.NullTime{}
+	workDay.SetWorkPeriods(periods)
+
+	out := &bytes.Buffer{}
+	w := exchange.NewTimewarriorWriter(out, []model.Project{{Id: 3, Name: "Acme Inc"}})
+	testutil.AssertNoErr(t, w.WriteWorkDay(workDay))
+	testutil.AssertNoErr(t, w.Flush())
+
+	start := periods[0].StartAt.UTC().Format("20060102T150405Z")
+	end := periods[0].EndAt.Time.UTC().Format("20060102T150405Z")
+	open := periods[1].StartAt.UTC().Format("20060102T150405Z")
+
+	testutil.AssertOutput(t, out, `inc `+start+` - `+end+` # "Acme Inc" billing # "Billing \"API\", v2"
+inc `+open+`
+`)
+}
+
+func TestReadTimewarrior(t *testing.T) {
+	input := `inc 20230901T070000Z - 20230901T103000Z # acme "code review" # "Release \"2.0\""
+inc 20230901T110000Z - 20230901T150000Z
+
+inc 20230902T070000Z
+inc 20230902T070000Z - 20230902T060000Z # acme
+inc 20230902T07000Z - 20230902T080000Z
+exc monday <9:00:00
+inc 20230902T070000Z - 20230902T080000Z # "unterminated
+`
+
+	workDays, rejections, err := exchange.ReadTimewarrior(strings.NewReader(input))
+	testutil.AssertNoErr(t, err)
+
+	wantRejections := []string{
+		"line 4: interval is still open",
+		"line 5: work period must not end before it starts",
+		"line 6: invalid start '20230902T07000Z'",
+		"line 7: expected an 'inc' interval, got 'exc monday <9:00:00'",
+		`line 8: unterminated quote in '"unterminated'`,
+	}
+	if len(rejections) != len(wantRejections) {
+		t.Fatalf("Expected %d rejections, got %v", len(wantRejections), rejections)
+	}
+	for i, want := range wantRejections {
+		if got := rejections[i].String(); got != want {
+			t.Errorf("Expected rejection '%s', got '%s'", want, got)
+		}
+	}
+
+	var periods []model.WorkPeriod
+	for _, workDay := range workDays {
+		periods = append(periods, workDay.WorkPeriods()...)
+	}
+
+	if len(periods) != 2 {
+		t.Fatalf("Expected 2 work periods, got %d", len(periods))
+	}
+
+	testutil.AssertAroundTime(t, "StartAt", periods[0].StartAt, time.Date(2023, 9, 1, 7, 0, 0, 0, time.UTC))
+	testutil.AssertAroundTime(t, "EndAt", periods[0].EndAt.Time, time.Date(2023, 9, 1, 10, 30, 0, 0, time.UTC))
+	if want := `Release "2.0", acme, code review`; periods[0].Note.String != want {
+		t.Errorf("Expected note '%s', got '%s'", want, periods[0].Note.String)
+	}
+	if periods[1].Note.Valid {
+		t.Errorf("Expected no note, got '%s'", periods[1].Note.String)
+	}
+}
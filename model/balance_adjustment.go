@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// BalanceAdjustment is a manual change to the flextime balance, such as a
+// balance carried over from another system or overtime that was paid out.
+type BalanceAdjustment struct {
+	Id         int
+	Date       time.Time
+	AmountMins int `db:"amount_mins"`
+	Reason     string
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+func NewBalanceAdjustment(date time.Time, amount time.Duration, reason string) BalanceAdjustment {
+	return BalanceAdjustment{
+		Date:       date,
+		AmountMins: int(amount.Minutes()),
+		Reason:     reason,
+	}
+}
+
+func (a *BalanceAdjustment) Amount() time.Duration {
+	return time.Duration(a.AmountMins) * time.Minute
+}
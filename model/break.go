@@ -0,0 +1,89 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Break is unpaid time taken during a work day. Breaks are recorded alongside
+// work periods rather than splitting them, and any time a break overlaps a
+// work period isn't counted as worked.
+type Break struct {
+	Id        int
+	WorkDayId int          `db:"work_day_id"`
+	StartAt   time.Time    `db:"start_at"`
+	EndAt     sql.NullTime `db:"end_at"`
+	Note      sql.NullString
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+var ErrBreakEndBeforeStart = errors.New("break must not end before it starts")
+
+func NewBreak(workDay WorkDay) Break {
+	return Break{
+		WorkDayId: workDay.Id,
+		StartAt:   time.Now(),
+	}
+}
+
+func (b *Break) SetEndAt(t time.Time) {
+	b.EndAt = sql.NullTime{Valid: !t.IsZero(), Time: t}
+}
+
+func (b *Break) SetNote(str string) {
+	b.Note = sql.NullString{Valid: str != "", String: str}
+}
+
+// Duration is how long the break lasted, or has lasted so far if it's ongoing.
+func (b *Break) Duration() time.Duration {
+	return b.endAt().Sub(b.StartAt)
+}
+
+func (b *Break) Validate() error {
+	if b.EndAt.Valid && b.EndAt.Time.Before(b.StartAt) {
+		return ErrBreakEndBeforeStart
+	}
+
+	return nil
+}
+
+// Overlaps reports whether the two breaks share any time. Open breaks are
+// treated as running indefinitely.
+func (b *Break) Overlaps(other Break) bool {
+	startsBeforeOtherEnds := !other.EndAt.Valid || b.StartAt.Before(other.EndAt.Time)
+	endsAfterOtherStarts := !b.EndAt.Valid || other.StartAt.Before(b.EndAt.Time)
+
+	return startsBeforeOtherEnds && endsAfterOtherStarts
+}
+
+// overlapWith returns how much of the break falls within the work period.
+func (b *Break) overlapWith(wp WorkPeriod) time.Duration {
+	periodEnd := wp.EndAt.Time
+	if !wp.EndAt.Valid {
+		periodEnd = time.Now()
+	}
+
+	start, end := b.StartAt, b.endAt()
+	if wp.StartAt.After(start) {
+		start = wp.StartAt
+	}
+	if periodEnd.Before(end) {
+		end = periodEnd
+	}
+
+	if end.Before(start) {
+		return 0
+	}
+
+	return end.Sub(start)
+}
+
+func (b *Break) endAt() time.Time {
+	if !b.EndAt.Valid {
+		return time.Now()
+	}
+
+	return b.EndAt.Time
+}
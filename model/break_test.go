@@ -0,0 +1,80 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+)
+
+func TestWorkDayTimeWorkedWithBreaks(t *testing.T) {
+	at := func(hour int, min int) time.Time {
+		return time.Date(2023, 9, 1, hour, min, 0, 0, time.Local)
+	}
+	period := func(start time.Time, end time.Time) model.WorkPeriod {
+		wp := model.WorkPeriod{StartAt: start}
+		wp.SetEndAt(end)
+		return wp
+	}
+	newBreak := func(start time.Time, end time.Time) model.Break {
+		b := model.Break{StartAt: start}
+		b.SetEndAt(end)
+		return b
+	}
+
+	wd := model.NewWorkDay(at(0, 0))
+	wd.SetWorkPeriods([]model.WorkPeriod{period(at(9, 0), at(12, 0)), period(at(13, 0), at(17, 0))})
+
+	testCases := []struct {
+		name           string
+		breaks         []model.Break
+		wantTimeWorked time.Duration
+		wantBreakTime  time.Duration
+	}{
+		{name: "no breaks", wantTimeWorked: 7 * time.Hour},
+		{
+			name:           "break within a work period",
+			breaks:         []model.Break{newBreak(at(10, 0), at(10, 30))},
+			wantTimeWorked: 6*time.Hour + 30*time.Minute,
+			wantBreakTime:  30 * time.Minute,
+		},
+		{
+			name:           "break spanning the gap between work periods",
+			breaks:         []model.Break{newBreak(at(11, 30), at(13, 30))},
+			wantTimeWorked: 6 * time.Hour,
+			wantBreakTime:  2 * time.Hour,
+		},
+		{
+			name:           "break outside work periods",
+			breaks:         []model.Break{newBreak(at(12, 0), at(13, 0))},
+			wantTimeWorked: 7 * time.Hour,
+			wantBreakTime:  time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wd.SetBreaks(tc.breaks)
+
+			if got := wd.TimeWorked(); got != tc.wantTimeWorked {
+				t.Errorf("TimeWorked: got %s, want %s", got, tc.wantTimeWorked)
+			}
+
+			if got := wd.BreakTime(); got != tc.wantBreakTime {
+				t.Errorf("BreakTime: got %s, want %s", got, tc.wantBreakTime)
+			}
+		})
+	}
+}
+
+func TestBreakValidate(t *testing.T) {
+	b := model.Break{StartAt: time.Date(2023, 9, 1, 12, 0, 0, 0, time.Local)}
+	if err := b.Validate(); err != nil {
+		t.Errorf("Expected an open break to be valid, got %v", err)
+	}
+
+	b.SetEndAt(b.StartAt.Add(-time.Minute))
+	if err := b.Validate(); err != model.ErrBreakEndBeforeStart {
+		t.Errorf("got %v, want %v", err, model.ErrBreakEndBeforeStart)
+	}
+}
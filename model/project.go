@@ -0,0 +1,26 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Project struct {
+	Id         int
+	Name       string
+	ArchivedAt sql.NullTime `db:"archived_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+	UpdatedAt  time.Time    `db:"updated_at"`
+}
+
+func NewProject(name string) Project {
+	return Project{Name: name}
+}
+
+func (p *Project) Archive() {
+	p.ArchivedAt = sql.NullTime{Valid: true, Time: time.Now()}
+}
+
+func (p *Project) IsArchived() bool {
+	return p.ArchivedAt.Valid
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+)
+
+// Schedule holds the expected work day length for each weekday, starting on
+// EffectiveFrom and lasting until the next schedule takes effect. Existing
+// work days keep their own length, so changing the schedule never rewrites
+// history.
+type Schedule struct {
+	Id            int
+	EffectiveFrom time.Time `db:"effective_from"`
+	MondayMins    int       `db:"monday_mins"`
+	TuesdayMins   int       `db:"tuesday_mins"`
+	WednesdayMins int       `db:"wednesday_mins"`
+	ThursdayMins  int       `db:"thursday_mins"`
+	FridayMins    int       `db:"friday_mins"`
+	SaturdayMins  int       `db:"saturday_mins"`
+	SundayMins    int       `db:"sunday_mins"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// NewSchedule creates a schedule with the same length from Monday to Friday
+// and nothing on weekends.
+func NewSchedule(effectiveFrom time.Time, weekdayLength time.Duration) Schedule {
+	schedule := Schedule{EffectiveFrom: effectiveFrom}
+	for day := time.Monday; day <= time.Friday; day++ {
+		schedule.SetLength(day, weekdayLength)
+	}
+
+	return schedule
+}
+
+func (s *Schedule) Length(day time.Weekday) time.Duration {
+	return time.Duration(*s.minsFor(day)) * time.Minute
+}
+
+func (s *Schedule) SetLength(day time.Weekday, length time.Duration) {
+	*s.minsFor(day) = int(length.Minutes())
+}
+
+func (s *Schedule) minsFor(day time.Weekday) *int {
+	switch day {
+	case time.Monday:
+		return &s.MondayMins
+	case time.Tuesday:
+		return &s.TuesdayMins
+	case time.Wednesday:
+		return &s.WednesdayMins
+	case time.Thursday:
+		return &s.ThursdayMins
+	case time.Friday:
+		return &s.FridayMins
+	case time.Saturday:
+		return &s.SaturdayMins
+	default:
+		return &s.SundayMins
+	}
+}
@@ -0,0 +1,41 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+)
+
+func TestNewSchedule(t *testing.T) {
+	schedule := model.NewSchedule(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local), 8*time.Hour)
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		want := 8 * time.Hour
+		if day == time.Saturday || day == time.Sunday {
+			want = 0
+		}
+
+		if got := schedule.Length(day); got != want {
+			t.Errorf("%s: got %v, want %v", day, got, want)
+		}
+	}
+}
+
+func TestScheduleSetLength(t *testing.T) {
+	schedule := model.NewSchedule(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local), 8*time.Hour)
+	schedule.SetLength(time.Friday, 4*time.Hour)
+	schedule.SetLength(time.Saturday, 90*time.Minute)
+
+	if schedule.FridayMins != 4*60 {
+		t.Errorf("got %d Friday minutes, want %d", schedule.FridayMins, 4*60)
+	}
+
+	if got := schedule.Length(time.Saturday); got != 90*time.Minute {
+		t.Errorf("got %v on Saturday, want %v", got, 90*time.Minute)
+	}
+
+	if got := schedule.Length(time.Thursday); got != 8*time.Hour {
+		t.Errorf("got %v on Thursday, want %v", got, 8*time.Hour)
+	}
+}
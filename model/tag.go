@@ -0,0 +1,39 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// noteTagRegex matches tags written inline in notes, such as "+meeting".
+var noteTagRegex = regexp.MustCompile(`(?:^|\s)\+([\w-]+)`)
+
+// ParseNoteTags returns the "+tag" style tags written in a note.
+func ParseNoteTags(note string) []string {
+	var tags []string
+	for _, match := range noteTagRegex.FindAllStringSubmatch(note, -1) {
+		tags = append(tags, match[1])
+	}
+
+	return NormalizeTags(tags)
+}
+
+// NormalizeTags lowercases, de-duplicates and sorts tags, dropping any
+// leading "+" and empty tags. It returns nil when no tags remain.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "+"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	sort.Strings(normalized)
+	return normalized
+}
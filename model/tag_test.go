@@ -0,0 +1,50 @@
+package model_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robyparr/wh/model"
+)
+
+func TestParseNoteTags(t *testing.T) {
+	testCases := []struct {
+		note string
+		want []string
+	}{
+		{note: "", want: nil},
+		{note: "No tags here.", want: nil},
+		{note: "+meeting with the team", want: []string{"meeting"}},
+		{note: "Reviewed PRs +review +Code-Review +review", want: []string{"code-review", "review"}},
+		{note: "a+b is not a tag", want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.note, func(t *testing.T) {
+			if got := model.ParseNoteTags(tc.note); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := model.NormalizeTags([]string{"+Meeting", "admin", " meeting ", "", "+"})
+	want := []string{"admin", "meeting"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestWorkPeriodHasAnyTag(t *testing.T) {
+	wp := model.WorkPeriod{}
+	wp.AddTags("meeting", "acme")
+
+	if !wp.HasAnyTag([]string{"admin", "+Meeting"}) {
+		t.Error("Expected work period to have the meeting tag")
+	}
+
+	if wp.HasAnyTag([]string{"admin"}) {
+		t.Error("Expected work period not to have the admin tag")
+	}
+}
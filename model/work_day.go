@@ -18,6 +18,7 @@ type WorkDay struct {
 	UpdatedAt  time.Time `db:"updated_at"`
 
 	workPeriods []WorkPeriod
+	breaks      []Break
 	timeWorked  *time.Duration
 }
 
@@ -38,11 +39,48 @@ func (w *WorkDay) SetNote(note string) {
 	w.Note = sql.NullString{String: note, Valid: true}
 }
 
+func (w *WorkDay) WorkPeriods() []WorkPeriod {
+	return w.workPeriods
+}
+
 func (w *WorkDay) SetWorkPeriods(periods []WorkPeriod) {
 	w.workPeriods = periods
 	w.timeWorked = nil
 }
 
+func (w *WorkDay) Breaks() []Break {
+	return w.breaks
+}
+
+func (w *WorkDay) SetBreaks(breaks []Break) {
+	w.breaks = breaks
+	w.timeWorked = nil
+}
+
+// BreakTime sums the work day's breaks.
+func (w *WorkDay) BreakTime() time.Duration {
+	var breakTime time.Duration
+	for _, b := range w.breaks {
+		breakTime += b.Duration()
+	}
+
+	return breakTime
+}
+
+// BreakTimeDuring sums the time the work day's breaks overlap the work
+// period.
+func (w *WorkDay) BreakTimeDuring(wp WorkPeriod) time.Duration {
+	var breakTime time.Duration
+	for _, b := range w.breaks {
+		breakTime += b.overlapWith(wp)
+	}
+
+	return breakTime
+}
+
+// TimeWorked sums the work day's work periods, minus any time spent on breaks
+// during them. A work period counts entirely towards the work day it started
+// on, even when it ends after midnight.
 func (w *WorkDay) TimeWorked() time.Duration {
 	if w.timeWorked != nil {
 		return *w.timeWorked
@@ -50,13 +88,17 @@ func (w *WorkDay) TimeWorked() time.Duration {
 
 	var timeWorked time.Duration
 	for _, wp := range w.workPeriods {
-		timeWorked += wp.TimeWorked()
+		timeWorked += wp.TimeWorked() - w.BreakTimeDuring(wp)
 	}
 
 	w.timeWorked = &timeWorked
 	return timeWorked
 }
 
+func (w *WorkDay) Balance() time.Duration {
+	return w.TimeWorked() - w.Length()
+}
+
 func (w *WorkDay) TimeRemaining() time.Duration {
 	return w.Length() - w.TimeWorked()
 }
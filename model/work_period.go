@@ -2,6 +2,7 @@ package model
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 )
 
@@ -11,10 +12,14 @@ type WorkPeriod struct {
 	StartAt   time.Time    `db:"start_at"`
 	EndAt     sql.NullTime `db:"end_at"`
 	Note      sql.NullString
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ProjectId sql.NullInt64 `db:"project_id"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+	Tags      []string      `db:"-"`
 }
 
+var ErrEndBeforeStart = errors.New("work period must not end before it starts")
+
 func NewWorkPeriod(workDay WorkDay) WorkPeriod {
 	return WorkPeriod{
 		WorkDayId: workDay.Id,
@@ -30,6 +35,27 @@ func (wp *WorkPeriod) SetNote(str string) {
 	wp.Note = sql.NullString{Valid: str != "", String: str}
 }
 
+func (wp *WorkPeriod) SetProject(project Project) {
+	wp.ProjectId = sql.NullInt64{Valid: project.Id != 0, Int64: int64(project.Id)}
+}
+
+func (wp *WorkPeriod) AddTags(tags ...string) {
+	wp.Tags = NormalizeTags(append(wp.Tags, tags...))
+}
+
+// HasAnyTag reports whether the work period has at least one of the tags.
+func (wp *WorkPeriod) HasAnyTag(tags []string) bool {
+	for _, tag := range NormalizeTags(tags) {
+		for _, wpTag := range wp.Tags {
+			if tag == wpTag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (wp *WorkPeriod) TimeWorked() time.Duration {
 	endAt := wp.EndAt.Time
 	if endAt.IsZero() {
@@ -38,3 +64,20 @@ func (wp *WorkPeriod) TimeWorked() time.Duration {
 
 	return endAt.Sub(wp.StartAt)
 }
+
+func (wp *WorkPeriod) Validate() error {
+	if wp.EndAt.Valid && wp.EndAt.Time.Before(wp.StartAt) {
+		return ErrEndBeforeStart
+	}
+
+	return nil
+}
+
+// Overlaps reports whether the two work periods share any time. Open work
+// periods are treated as running indefinitely.
+func (wp *WorkPeriod) Overlaps(other WorkPeriod) bool {
+	startsBeforeOtherEnds := !other.EndAt.Valid || wp.StartAt.Before(other.EndAt.Time)
+	endsAfterOtherStarts := !wp.EndAt.Valid || other.StartAt.Before(wp.EndAt.Time)
+
+	return startsBeforeOtherEnds && endsAfterOtherStarts
+}
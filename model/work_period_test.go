@@ -0,0 +1,74 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+)
+
+func TestWorkPeriodValidate(t *testing.T) {
+	start := time.Date(2023, 9, 1, 9, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name    string
+		endAt   time.Time
+		wantErr error
+	}{
+		{name: "open", wantErr: nil},
+		{name: "ends after start", endAt: start.Add(time.Hour), wantErr: nil},
+		{name: "ends at start", endAt: start, wantErr: nil},
+		{name: "ends before start", endAt: start.Add(-time.Minute), wantErr: model.ErrEndBeforeStart},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wp := model.WorkPeriod{StartAt: start}
+			wp.SetEndAt(tc.endAt)
+
+			if got := wp.Validate(); got != tc.wantErr {
+				t.Errorf("got %v, want %v", got, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkPeriodOverlaps(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2023, 9, 1, hour, 0, 0, 0, time.Local)
+	}
+	period := func(start int, end int) model.WorkPeriod {
+		wp := model.WorkPeriod{StartAt: at(start)}
+		if end != 0 {
+			wp.SetEndAt(at(end))
+		}
+
+		return wp
+	}
+
+	testCases := []struct {
+		name  string
+		a     model.WorkPeriod
+		b     model.WorkPeriod
+		wants bool
+	}{
+		{name: "before", a: period(9, 10), b: period(11, 12), wants: false},
+		{name: "adjacent", a: period(9, 10), b: period(10, 12), wants: false},
+		{name: "overlapping", a: period(9, 11), b: period(10, 12), wants: true},
+		{name: "contained", a: period(9, 12), b: period(10, 11), wants: true},
+		{name: "open after", a: period(9, 10), b: period(11, 0), wants: false},
+		{name: "open during", a: period(9, 12), b: period(11, 0), wants: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.Overlaps(tc.b); got != tc.wants {
+				t.Errorf("a.Overlaps(b): got %v, want %v", got, tc.wants)
+			}
+
+			if got := tc.b.Overlaps(tc.a); got != tc.wants {
+				t.Errorf("b.Overlaps(a): got %v, want %v", got, tc.wants)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/robyparr/wh/model"
+)
+
+func (r *Repo) CreateBalanceAdjustment(adjustment model.BalanceAdjustment) (model.BalanceAdjustment, error) {
+	now := time.Now()
+	adjustment.CreatedAt = now
+	adjustment.UpdatedAt = now
+
+	result, err := r.db.NamedExec(`
+		INSERT INTO balance_adjustments (date, amount_mins, reason, created_at, updated_at)
+		VALUES (:date, :amount_mins, :reason, :created_at, :updated_at)
+	`, adjustment)
+
+	if err != nil {
+		return model.BalanceAdjustment{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.BalanceAdjustment{}, err
+	}
+
+	adjustment.Id = int(id)
+	return adjustment, nil
+}
+
+func (r *Repo) GetBalanceAdjustmentsInRange(from time.Time, to time.Time) ([]model.BalanceAdjustment, error) {
+	var adjustments []model.BalanceAdjustment
+	if err := r.db.Select(&adjustments, "SELECT * FROM balance_adjustments WHERE date BETWEEN ? AND ? ORDER BY date, id", from, to); err != nil {
+		return []model.BalanceAdjustment{}, err
+	}
+
+	return adjustments, nil
+}
@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestCreateBalanceAdjustment(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	adjustment := model.NewBalanceAdjustment(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local), 3*time.Hour, "Carried over.")
+	got, err := repo.CreateBalanceAdjustment(adjustment)
+	testutil.AssertNoErr(t, err)
+
+	want := adjustment
+	want.Id = 1
+	want.CreatedAt = time.Now()
+	want.UpdatedAt = time.Now()
+
+	testutil.AssertEqualStructs(t, got, want)
+}
+
+func TestGetBalanceAdjustmentsInRange(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	var adjustments []model.BalanceAdjustment
+	for day := 1; day <= 3; day++ {
+		date := time.Date(2023, 9, day, 0, 0, 0, 0, time.Local)
+		adjustment, err := repo.CreateBalanceAdjustment(model.NewBalanceAdjustment(date, time.Hour, "Adjustment."))
+		testutil.AssertNoErr(t, err)
+
+		adjustments = append(adjustments, adjustment)
+	}
+
+	got, err := repo.GetBalanceAdjustmentsInRange(adjustments[1].Date, adjustments[2].Date)
+	testutil.AssertNoErr(t, err)
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 balance adjustments, got %d", len(got))
+	}
+
+	testutil.AssertEqualStructs(t, got[0], adjustments[1])
+	testutil.AssertEqualStructs(t, got[1], adjustments[2])
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robyparr/wh/model"
+)
+
+func (r *Repo) CreateBreak(b model.Break) (model.Break, error) {
+	now := time.Now()
+	b.Id = 0
+	b.CreatedAt = now
+	b.UpdatedAt = now
+
+	err := r.withTx(func(tx *sqlx.Tx) error {
+		var err error
+		b, err = insertBreak(tx, b)
+		return err
+	})
+
+	if err != nil {
+		return model.Break{}, err
+	}
+
+	return b, nil
+}
+
+// insertBreak inserts the break as is, keeping its timestamps and its ID when
+// it has one.
+func insertBreak(tx *sqlx.Tx, b model.Break) (model.Break, error) {
+	result, err := tx.NamedExec(`
+		INSERT INTO breaks (id, work_day_id, start_at, end_at, note, created_at, updated_at)
+		VALUES (NULLIF(:id, 0), :work_day_id, :start_at, :end_at, :note, :created_at, :updated_at)
+	`, b)
+
+	if err != nil {
+		return model.Break{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.Break{}, err
+	}
+
+	b.Id = int(id)
+	return b, nil
+}
+
+func (r *Repo) GetBreaks(workDay model.WorkDay) ([]model.Break, error) {
+	var breaks []model.Break
+	if err := r.db.Select(&breaks, "SELECT * FROM breaks WHERE work_day_id = ? ORDER BY start_at", workDay.Id); err != nil {
+		return []model.Break{}, err
+	}
+
+	return breaks, nil
+}
+
+// GetOpenBreak returns the break that hasn't ended yet, whichever work day it
+// started on.
+func (r *Repo) GetOpenBreak() (model.Break, error) {
+	var b model.Break
+	if err := r.db.Get(&b, "SELECT * FROM breaks WHERE end_at IS NULL ORDER BY start_at DESC LIMIT 1"); err != nil {
+		if err == sql.ErrNoRows {
+			return model.Break{}, nil
+		}
+
+		return model.Break{}, err
+	}
+
+	return b, nil
+}
+
+func (r *Repo) UpdateBreak(b model.Break) (model.Break, error) {
+	b.UpdatedAt = time.Now()
+
+	result, err := r.db.NamedExec(`
+		UPDATE breaks
+		SET start_at = :start_at,
+				end_at = :end_at,
+				note = :note,
+				updated_at = :updated_at
+		WHERE id = :id
+	`, b)
+
+	if err != nil {
+		return model.Break{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Break{}, err
+	}
+
+	if rowsAffected == 0 {
+		return model.Break{}, errNoUpdatedRows
+	}
+
+	return b, nil
+}
+
+// loadBreaks sets the breaks of each work day in place.
+func (r *Repo) loadBreaks(workDays []model.WorkDay, from time.Time, to time.Time) error {
+	var breaks []model.Break
+	if err := r.db.Select(&breaks, `
+		SELECT breaks.*
+		FROM breaks
+		INNER JOIN work_days ON work_days.id = breaks.work_day_id
+		WHERE work_days.date BETWEEN ? AND ?
+		ORDER BY breaks.start_at
+	`, from, to); err != nil {
+		return err
+	}
+
+	breaksByDay := make(map[int][]model.Break)
+	for _, b := range breaks {
+		breaksByDay[b.WorkDayId] = append(breaksByDay[b.WorkDayId], b)
+	}
+
+	for i := range workDays {
+		workDays[i].SetBreaks(breaksByDay[workDays[i].Id])
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestBreaks(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	workDay, err := repo.CreateWorkDay(model.NewWorkDay(date))
+	testutil.AssertNoErr(t, err)
+
+	t.Run("no open break", func(t *testing.T) {
+		got, err := repo.GetOpenBreak()
+		testutil.AssertNoErr(t, err)
+
+		if got.Id != 0 {
+			t.Errorf("Expected an empty break, got %+v", got)
+		}
+	})
+
+	b := model.NewBreak(workDay)
+	b.StartAt = date.Add(12 * time.Hour)
+	b.SetNote("Lunch")
+	b, err = repo.CreateBreak(b)
+	testutil.AssertNoErr(t, err)
+
+	want := model.Break{
+		Id:        1,
+		WorkDayId: workDay.Id,
+		StartAt:   date.Add(12 * time.Hour),
+		Note:      sql.NullString{Valid: true, String: "Lunch"},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	testutil.AssertEqualStructs(t, b, want)
+
+	t.Run("open break", func(t *testing.T) {
+		got, err := repo.GetOpenBreak()
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, got, b)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		b.SetEndAt(date.Add(12*time.Hour + 30*time.Minute))
+		_, err := repo.UpdateBreak(b)
+		testutil.AssertNoErr(t, err)
+
+		breaks, err := repo.GetBreaks(workDay)
+		testutil.AssertNoErr(t, err)
+		if len(breaks) != 1 {
+			t.Fatalf("got %d breaks, want 1", len(breaks))
+		}
+		testutil.AssertEqualStructs(t, breaks[0].EndAt, b.EndAt)
+	})
+
+	t.Run("loaded with work days", func(t *testing.T) {
+		workDays, err := repo.GetWorkDaysInRange(date, date)
+		testutil.AssertNoErr(t, err)
+
+		if got := workDays[0].BreakTime(); got != 30*time.Minute {
+			t.Errorf("got break time %s, want 30m", got)
+		}
+	})
+
+	t.Run("deleted with the work day", func(t *testing.T) {
+		testutil.AssertNoErr(t, repo.DeleteWorkDay(workDay))
+
+		breaks, err := repo.GetBreaks(workDay)
+		testutil.AssertNoErr(t, err)
+		if len(breaks) != 0 {
+			t.Errorf("got %d breaks, want 0", len(breaks))
+		}
+	})
+}
@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robyparr/wh/model"
+)
+
+// ImportConflict decides what happens to an imported work day whose date
+// already has a work day.
+type ImportConflict string
+
+const (
+	// ImportSkip keeps the existing work day and ignores the imported one.
+	ImportSkip ImportConflict = "skip"
+	// ImportOverwrite replaces the existing work day, along with its work
+	// periods and breaks, with the imported one.
+	ImportOverwrite ImportConflict = "overwrite"
+	// ImportMerge keeps the existing work day and adds the imported work
+	// periods and breaks that don't overlap existing ones.
+	ImportMerge ImportConflict = "merge"
+)
+
+// ImportConflicts lists the valid ImportConflict values.
+var ImportConflicts = []ImportConflict{ImportSkip, ImportOverwrite, ImportMerge}
+
+// ImportResult counts what an import did, or would do on a dry run.
+type ImportResult struct {
+	DaysCreated     int
+	DaysOverwritten int
+	DaysMerged      int
+	DaysSkipped     int
+	PeriodsCreated  int
+	PeriodsSkipped  int
+	BreaksCreated   int
+	BreaksSkipped   int
+	// IdsChanged counts the imported records that got a new ID because
+	// another record already had theirs.
+	IdsChanged int
+}
+
+var errDryRun = errors.New("dry run")
+
+// ImportWorkDays saves the work days, with their work periods and breaks, in
+// a single transaction so that either everything is imported or nothing is.
+// Timestamps are kept, or set to now when missing. IDs are kept too unless
+// another row already has them, in which case a new one is assigned. Work
+// periods refer to the given projects by ID; projects are matched to existing
+// ones by name and created when missing. The install ID, when given, replaces
+// the database's own if it has no work periods yet, so that a restored
+// database exports the same calendar events. On a dry run the transaction is
+// rolled back.
+func (r *Repo) ImportWorkDays(installId string, projects []model.Project, workDays []model.WorkDay, conflict ImportConflict, dryRun bool) (ImportResult, error) {
+	var result ImportResult
+	err := r.withTx(func(tx *sqlx.Tx) error {
+		if installId != "" {
+			if err := importInstallId(tx, installId); err != nil {
+				return err
+			}
+		}
+
+		projectIds, err := importProjects(tx, projects, &result)
+		if err != nil {
+			return err
+		}
+
+		for _, workDay := range workDays {
+			if err := importWorkDay(tx, workDay, projectIds, conflict, &result); err != nil {
+				return fmt.Errorf("error importing work day on %s: %v", workDay.Date.Format("2006-01-02"), err)
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if err != nil && err != errDryRun {
+		return ImportResult{}, err
+	}
+
+	return result, nil
+}
+
+func importInstallId(tx *sqlx.Tx, installId string) error {
+	var periodCount int
+	if err := tx.Get(&periodCount, "SELECT COUNT(*) FROM work_periods"); err != nil {
+		return err
+	}
+
+	if periodCount > 0 {
+		return nil
+	}
+
+	_, err := tx.Exec("UPDATE settings SET value = ? WHERE name = 'install_id'", installId)
+	return err
+}
+
+// importId returns the imported ID when no row of the table has it yet, or 0
+// to have a new one assigned. The table name is never user input.
+func importId(tx *sqlx.Tx, table string, id int, result *ImportResult) (int, error) {
+	if id == 0 {
+		return 0, nil
+	}
+
+	var count int
+	if err := tx.Get(&count, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = ?", table), id); err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		result.IdsChanged++
+		return 0, nil
+	}
+
+	return id, nil
+}
+
+// importProjects returns the IDs of the projects in the database keyed by
+// their imported IDs.
+func importProjects(tx *sqlx.Tx, projects []model.Project, result *ImportResult) (map[int64]int64, error) {
+	projectIds := make(map[int64]int64)
+	for _, project := range projects {
+		var existing model.Project
+		err := tx.Get(&existing, "SELECT * FROM projects WHERE name = ?", project.Name)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		if existing.Id == 0 {
+			inserted := project
+			inserted.Id, err = importId(tx, "projects", project.Id, result)
+			if err != nil {
+				return nil, err
+			}
+
+			insertResult, err := tx.NamedExec(`
+				INSERT INTO projects (id, name, archived_at, created_at, updated_at)
+				VALUES (NULLIF(:id, 0), :name, :archived_at, :created_at, :updated_at)
+			`, inserted)
+			if err != nil {
+				return nil, err
+			}
+
+			id, err := insertResult.LastInsertId()
+			if err != nil {
+				return nil, err
+			}
+
+			existing.Id = int(id)
+		}
+
+		projectIds[int64(project.Id)] = int64(existing.Id)
+	}
+
+	return projectIds, nil
+}
+
+func importWorkDay(tx *sqlx.Tx, workDay model.WorkDay, projectIds map[int64]int64, conflict ImportConflict, result *ImportResult) error {
+	now := time.Now()
+	defaultTimestamps(&workDay.CreatedAt, &workDay.UpdatedAt, now)
+
+	var existing model.WorkDay
+	err := tx.Get(&existing, "SELECT * FROM work_days WHERE date = ?", workDay.Date)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	var existingPeriods []model.WorkPeriod
+	var existingBreaks []model.Break
+
+	switch {
+	case existing.Id == 0:
+		workDay.Id, err = importId(tx, "work_days", workDay.Id, result)
+		if err != nil {
+			return err
+		}
+
+		existing, err = insertWorkDay(tx, workDay)
+		if err != nil {
+			return err
+		}
+
+		result.DaysCreated++
+	case conflict == ImportSkip:
+		result.DaysSkipped++
+		return nil
+	case conflict == ImportOverwrite:
+		if err := deleteWorkDayEntries(tx, existing.Id); err != nil {
+			return err
+		}
+
+		workDay.Id = existing.Id
+		if _, err := tx.NamedExec(`
+			UPDATE work_days
+			SET length_mins = :length_mins,
+					note = :note,
+					created_at = :created_at,
+					updated_at = :updated_at
+			WHERE id = :id
+		`, workDay); err != nil {
+			return err
+		}
+
+		result.DaysOverwritten++
+	case conflict == ImportMerge:
+		if err := tx.Select(&existingPeriods, "SELECT * FROM work_periods WHERE work_day_id = ?", existing.Id); err != nil {
+			return err
+		}
+
+		if err := tx.Select(&existingBreaks, "SELECT * FROM breaks WHERE work_day_id = ?", existing.Id); err != nil {
+			return err
+		}
+
+		result.DaysMerged++
+	default:
+		return fmt.Errorf("unknown import conflict handling '%s'", conflict)
+	}
+
+periods:
+	for _, period := range workDay.WorkPeriods() {
+		for _, other := range existingPeriods {
+			if period.Overlaps(other) {
+				result.PeriodsSkipped++
+				continue periods
+			}
+		}
+
+		period.WorkDayId = existing.Id
+		defaultTimestamps(&period.CreatedAt, &period.UpdatedAt, now)
+		if period.ProjectId.Valid {
+			id, ok := projectIds[period.ProjectId.Int64]
+			if !ok {
+				return fmt.Errorf("work period refers to unknown project #%d", period.ProjectId.Int64)
+			}
+
+			period.ProjectId.Int64 = id
+		}
+
+		// Only one work period can be ongoing at a time.
+		if !period.EndAt.Valid {
+			var openCount int
+			if err := tx.Get(&openCount, "SELECT COUNT(*) FROM work_periods WHERE end_at IS NULL"); err != nil {
+				return err
+			}
+
+			if openCount > 0 {
+				return fmt.Errorf("work period started %s is still open, but another work period is already open", period.StartAt.Format(time.RFC3339))
+			}
+		}
+
+		period.Id, err = importId(tx, "work_periods", period.Id, result)
+		if err != nil {
+			return err
+		}
+
+		if _, err := insertWorkPeriod(tx, period); err != nil {
+			return err
+		}
+
+		result.PeriodsCreated++
+	}
+
+breaks:
+	for _, b := range workDay.Breaks() {
+		for _, other := range existingBreaks {
+			if b.Overlaps(other) {
+				result.BreaksSkipped++
+				continue breaks
+			}
+		}
+
+		b.WorkDayId = existing.Id
+		defaultTimestamps(&b.CreatedAt, &b.UpdatedAt, now)
+		b.Id, err = importId(tx, "breaks", b.Id, result)
+		if err != nil {
+			return err
+		}
+
+		if _, err := insertBreak(tx, b); err != nil {
+			return err
+		}
+
+		result.BreaksCreated++
+	}
+
+	return nil
+}
+
+// defaultTimestamps sets timestamps that weren't imported, as some formats
+// don't record them.
+func defaultTimestamps(createdAt *time.Time, updatedAt *time.Time, now time.Time) {
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+
+	if updatedAt.IsZero() {
+		*updatedAt = *createdAt
+	}
+}
+
+// deleteWorkDayEntries deletes the work periods, their tags and the breaks of
+// the work day, but not the work day itself.
+func deleteWorkDayEntries(tx *sqlx.Tx, workDayId int) error {
+	if _, err := tx.Exec(`
+		DELETE FROM work_period_tags
+		WHERE work_period_id IN (SELECT id FROM work_periods WHERE work_day_id = ?)
+	`, workDayId); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM work_periods WHERE work_day_id = ?", workDayId); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec("DELETE FROM breaks WHERE work_day_id = ?", workDayId)
+	return err
+}
@@ -0,0 +1,292 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func importWorkDay(date time.Time, projectId int64, spans ...[2]int) model.WorkDay {
+	wd := model.NewWorkDay(date)
+	wd.SetNote("Imported")
+	wd.CreatedAt = date.Add(-24 * time.Hour)
+	wd.UpdatedAt = date.Add(-24 * time.Hour)
+
+	var periods []model.WorkPeriod
+	for _, span := range spans {
+		wp := model.WorkPeriod{StartAt: date.Add(time.Duration(span[0]) * time.Hour)}
+		wp.SetEndAt(date.Add(time.Duration(span[1]) * time.Hour))
+		wp.SetProject(model.Project{Id: int(projectId)})
+		wp.AddTags("imported")
+		wp.CreatedAt = wp.StartAt
+		wp.UpdatedAt = wp.EndAt.Time
+		periods = append(periods, wp)
+	}
+
+	wd.SetWorkPeriods(periods)
+	return wd
+}
+
+func TestImportWorkDays(t *testing.T) {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	projects := []model.Project{{Id: 42, Name: "acme", CreatedAt: date, UpdatedAt: date}}
+
+	setup := func(t *testing.T) *repository.Repo {
+		repo := testutil.NewRepo(t)
+
+		wd, err := repo.CreateWorkDay(model.NewWorkDay(date))
+		testutil.AssertNoErr(t, err)
+
+		wp := model.NewWorkPeriod(wd)
+		wp.StartAt = date.Add(9 * time.Hour)
+		wp.SetEndAt(date.Add(12 * time.Hour))
+		_, err = repo.CreateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+
+		return repo
+	}
+
+	imported := []model.WorkDay{
+		importWorkDay(date, 42, [2]int{11, 13}, [2]int{13, 17}),
+		importWorkDay(date.AddDate(0, 0, 1), 42, [2]int{9, 17}),
+	}
+
+	t.Run("new work days keep their timestamps", func(t *testing.T) {
+		repo := setup(t)
+
+		result, err := repo.ImportWorkDays("", projects, imported, repository.ImportSkip, false)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, result, repository.ImportResult{DaysCreated: 1, DaysSkipped: 1, PeriodsCreated: 1})
+
+		got, err := repo.GetWorkDayByDate(date.AddDate(0, 0, 1))
+		testutil.AssertNoErr(t, err)
+		testutil.AssertAroundTime(t, "CreatedAt", got.CreatedAt, date)
+		if got.Note.String != "Imported" {
+			t.Errorf("Expected the imported note, got '%s'", got.Note.String)
+		}
+
+		periods, err := repo.GetWorkPeriods(got)
+		testutil.AssertNoErr(t, err)
+		project, err := repo.GetProjectByName("acme")
+		testutil.AssertNoErr(t, err)
+
+		if len(periods) != 1 {
+			t.Fatalf("Expected 1 work period, got %d", len(periods))
+		}
+		if periods[0].ProjectId.Int64 != int64(project.Id) {
+			t.Errorf("Expected project #%d, got #%d", project.Id, periods[0].ProjectId.Int64)
+		}
+		if len(periods[0].Tags) != 1 || periods[0].Tags[0] != "imported" {
+			t.Errorf("Expected the imported tag, got %v", periods[0].Tags)
+		}
+		testutil.AssertAroundTime(t, "period CreatedAt", periods[0].CreatedAt, date.AddDate(0, 0, 1).Add(9*time.Hour))
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		repo := setup(t)
+
+		result, err := repo.ImportWorkDays("", projects, imported, repository.ImportOverwrite, false)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, result, repository.ImportResult{DaysCreated: 1, DaysOverwritten: 1, PeriodsCreated: 3})
+
+		got, err := repo.GetWorkDayByDate(date)
+		testutil.AssertNoErr(t, err)
+		periods, err := repo.GetWorkPeriods(got)
+		testutil.AssertNoErr(t, err)
+
+		if len(periods) != 2 || !periods[0].StartAt.Equal(date.Add(11*time.Hour)) {
+			t.Errorf("Expected only the imported work periods, got %+v", periods)
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		repo := setup(t)
+
+		result, err := repo.ImportWorkDays("", projects, imported, repository.ImportMerge, false)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, result, repository.ImportResult{DaysCreated: 1, DaysMerged: 1, PeriodsCreated: 2, PeriodsSkipped: 1})
+
+		got, err := repo.GetWorkDayByDate(date)
+		testutil.AssertNoErr(t, err)
+		periods, err := repo.GetWorkPeriods(got)
+		testutil.AssertNoErr(t, err)
+
+		if len(periods) != 2 || !periods[1].StartAt.Equal(date.Add(13*time.Hour)) {
+			t.Errorf("Expected the existing and the non-overlapping work period, got %+v", periods)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		repo := setup(t)
+
+		result, err := repo.ImportWorkDays("", projects, imported, repository.ImportOverwrite, true)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, result, repository.ImportResult{DaysCreated: 1, DaysOverwritten: 1, PeriodsCreated: 3})
+
+		count, err := repo.GetWorkDayCount()
+		testutil.AssertNoErr(t, err)
+		if count != 1 {
+			t.Errorf("Expected the dry run to save nothing, got %d work days", count)
+		}
+	})
+
+	t.Run("all or nothing", func(t *testing.T) {
+		repo := setup(t)
+
+		unknownProject := importWorkDay(date.AddDate(0, 0, 2), 7, [2]int{9, 17})
+		_, err := repo.ImportWorkDays("", projects, append(imported, unknownProject), repository.ImportOverwrite, false)
+		if err == nil {
+			t.Fatal("Expected an error but got none")
+		}
+
+		count, err := repo.GetWorkDayCount()
+		testutil.AssertNoErr(t, err)
+		if count != 1 {
+			t.Errorf("Expected the failed import to save nothing, got %d work days", count)
+		}
+	})
+	t.Run("open work period while another is open", func(t *testing.T) {
+		repo := setup(t)
+
+		wd, err := repo.GetWorkDayByDate(date)
+		testutil.AssertNoErr(t, err)
+		open := model.NewWorkPeriod(wd)
+		open.StartAt = date.Add(13 * time.Hour)
+		_, err = repo.CreateWorkPeriod(open)
+		testutil.AssertNoErr(t, err)
+
+		openDay := importWorkDay(date.AddDate(0, 0, 2), 42)
+		openDay.SetWorkPeriods([]model.WorkPeriod{{StartAt: date.AddDate(0, 0, 2).Add(9 * time.Hour)}})
+		_, err = repo.ImportWorkDays("", projects, []model.WorkDay{openDay}, repository.ImportSkip, false)
+		if err == nil {
+			t.Fatal("Expected an error but got none")
+		}
+
+		count, err := repo.GetWorkDayCount()
+		testutil.AssertNoErr(t, err)
+		if count != 1 {
+			t.Errorf("Expected the failed import to save nothing, got %d work days", count)
+		}
+	})
+
+	t.Run("open work period replacing the open one", func(t *testing.T) {
+		repo := setup(t)
+
+		wd, err := repo.GetWorkDayByDate(date)
+		testutil.AssertNoErr(t, err)
+		open := model.NewWorkPeriod(wd)
+		open.StartAt = date.Add(13 * time.Hour)
+		_, err = repo.CreateWorkPeriod(open)
+		testutil.AssertNoErr(t, err)
+
+		openDay := importWorkDay(date, 42)
+		openDay.SetWorkPeriods([]model.WorkPeriod{{StartAt: date.Add(14 * time.Hour)}})
+		_, err = repo.ImportWorkDays("", projects, []model.WorkDay{openDay}, repository.ImportOverwrite, false)
+		testutil.AssertNoErr(t, err)
+
+		got, err := repo.GetOpenWorkPeriod()
+		testutil.AssertNoErr(t, err)
+		if !got.StartAt.Equal(date.Add(14 * time.Hour)) {
+			t.Errorf("Expected the imported work period to be open, got %+v", got)
+		}
+	})
+}
+
+func TestImportWorkDaysIds(t *testing.T) {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+	projects := []model.Project{{Id: 42, Name: "acme", CreatedAt: date, UpdatedAt: date}}
+
+	imported := func() []model.WorkDay {
+		wd := importWorkDay(date, 42, [2]int{9, 12})
+		wd.Id = 7
+		periods := wd.WorkPeriods()
+		periods[0].Id = 11
+		wd.SetWorkPeriods(periods)
+
+		b := model.Break{Id: 5, StartAt: date.Add(10 * time.Hour)}
+		b.SetEndAt(date.Add(10*time.Hour + 15*time.Minute))
+		wd.SetBreaks([]model.Break{b})
+		return []model.WorkDay{wd}
+	}
+
+	t.Run("empty database keeps IDs and the install ID", func(t *testing.T) {
+		repo := testutil.NewRepo(t)
+
+		result, err := repo.ImportWorkDays("0123abcd", projects, imported(), repository.ImportSkip, false)
+		testutil.AssertNoErr(t, err)
+		if result.IdsChanged != 0 {
+			t.Errorf("Expected no IDs to change, got %d", result.IdsChanged)
+		}
+
+		wd, err := repo.GetWorkDayByDate(date)
+		testutil.AssertNoErr(t, err)
+		if wd.Id != 7 {
+			t.Errorf("Expected work day #7, got #%d", wd.Id)
+		}
+
+		period, err := repo.GetWorkPeriodById(11)
+		testutil.AssertNoErr(t, err)
+		if period.WorkDayId != 7 || period.ProjectId.Int64 != 42 {
+			t.Errorf("Expected work period #11 on work day #7 for project #42, got %+v", period)
+		}
+		if len(period.Tags) != 1 || period.Tags[0] != "imported" {
+			t.Errorf("Expected the imported tags, got %v", period.Tags)
+		}
+
+		breaks, err := repo.GetBreaks(wd)
+		testutil.AssertNoErr(t, err)
+		if len(breaks) != 1 || breaks[0].Id != 5 {
+			t.Errorf("Expected break #5, got %+v", breaks)
+		}
+
+		installId, err := repo.GetInstallId()
+		testutil.AssertNoErr(t, err)
+		if installId != "0123abcd" {
+			t.Errorf("Expected the imported install ID, got '%s'", installId)
+		}
+	})
+
+	t.Run("taken IDs are renumbered", func(t *testing.T) {
+		repo := testutil.NewRepo(t)
+		installId, err := repo.GetInstallId()
+		testutil.AssertNoErr(t, err)
+
+		for day := 0; day < 7; day++ {
+			wd, err := repo.CreateWorkDay(model.NewWorkDay(date.AddDate(0, 0, -10+day)))
+			testutil.AssertNoErr(t, err)
+
+			wp := model.NewWorkPeriod(wd)
+			wp.StartAt = wd.Date.Add(9 * time.Hour)
+			wp.SetEndAt(wd.Date.Add(17 * time.Hour))
+			_, err = repo.CreateWorkPeriod(wp)
+			testutil.AssertNoErr(t, err)
+		}
+
+		result, err := repo.ImportWorkDays("0123abcd", projects, imported(), repository.ImportSkip, false)
+		testutil.AssertNoErr(t, err)
+		if result.IdsChanged != 1 {
+			t.Errorf("Expected the work day's ID to change, got %d changed IDs", result.IdsChanged)
+		}
+
+		wd, err := repo.GetWorkDayByDate(date)
+		testutil.AssertNoErr(t, err)
+		if wd.Id == 7 {
+			t.Error("Expected the work day to get a new ID")
+		}
+
+		period, err := repo.GetWorkPeriodById(11)
+		testutil.AssertNoErr(t, err)
+		if period.WorkDayId != wd.Id {
+			t.Errorf("Expected work period #11 on work day #%d, got %+v", wd.Id, period)
+		}
+
+		got, err := repo.GetInstallId()
+		testutil.AssertNoErr(t, err)
+		if got != installId {
+			t.Errorf("Expected the install ID to stay '%s', got '%s'", installId, got)
+		}
+	})
+}
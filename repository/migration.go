@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationFileRegex matches migration file names such as 0002_add_projects.sql.
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.sql$`)
+
+const migrationsTableSchema string = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version			INTEGER PRIMARY KEY,
+		name				TEXT NOT NULL,
+		applied_at	DATETIME NOT NULL
+	);
+`
+
+type Migration struct {
+	Version   int
+	Name      string
+	AppliedAt sql.NullTime `db:"applied_at"`
+
+	sql string
+}
+
+// Migrate applies every pending migration, each in its own transaction, and
+// returns the ones it applied.
+func (r *Repo) Migrate() ([]Migration, error) {
+	migrations, err := r.GetMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if m.AppliedAt.Valid {
+			continue
+		}
+
+		m.AppliedAt = sql.NullTime{Valid: true, Time: time.Now()}
+		err := r.withTx(func(tx *sqlx.Tx) error {
+			if _, err := tx.Exec(m.sql); err != nil {
+				return err
+			}
+
+			_, err := tx.NamedExec(`
+				INSERT INTO schema_migrations (version, name, applied_at)
+				VALUES (:version, :name, :applied_at)
+			`, m)
+			return err
+		})
+
+		if err != nil {
+			return applied, fmt.Errorf("error applying migration %d (%s): %v", m.Version, m.Name, err)
+		}
+
+		applied = append(applied, m)
+	}
+
+	return applied, nil
+}
+
+// GetMigrations returns every known migration in order, with AppliedAt set
+// for the ones that have been applied.
+func (r *Repo) GetMigrations() ([]Migration, error) {
+	if _, err := r.db.Exec(migrationsTableSchema); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	if err := r.db.Select(&applied, "SELECT * FROM schema_migrations"); err != nil {
+		return nil, err
+	}
+
+	appliedAt := make(map[int]sql.NullTime)
+	for _, m := range applied {
+		appliedAt[m.Version] = m.AppliedAt
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range migrations {
+		migrations[i].AppliedAt = appliedAt[migrations[i].Version]
+	}
+
+	return migrations, nil
+}
+
+func loadMigrations() ([]Migration, error) {
+	paths, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, p := range paths {
+		matches := migrationFileRegex.FindStringSubmatch(path.Base(p))
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", p)
+		}
+
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := migrationFiles.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: matches[2], sql: string(contents)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robyparr/wh/repository"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestMigrate(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	migrations, err := repo.GetMigrations()
+	testutil.AssertNoErr(t, err)
+
+	if len(migrations) == 0 {
+		t.Fatal("Expected at least one migration")
+	}
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("Expected migration %d to have version %d, got %d", i, i+1, m.Version)
+		}
+
+		if !m.AppliedAt.Valid {
+			t.Errorf("Expected migration %d (%s) to be applied", m.Version, m.Name)
+		}
+	}
+
+	applied, err := repo.Migrate()
+	testutil.AssertNoErr(t, err)
+
+	if len(applied) != 0 {
+		t.Errorf("Expected no pending migrations, got %d", len(applied))
+	}
+}
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.sqlite")
+
+	db, err := sqlx.Open("sqlite3", path)
+	testutil.AssertNoErr(t, err)
+
+	_, err = db.Exec(`
+		CREATE TABLE work_days (
+			id INTEGER PRIMARY KEY,
+			date DATETIME NOT NULL,
+			length_mins INTEGER NOT NULL,
+			note TEXT,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+
+		INSERT INTO work_days (date, length_mins, created_at, updated_at)
+		VALUES ('2023-09-01 00:00:00+00:00', 450, '2023-09-01 00:00:00+00:00', '2023-09-01 00:00:00+00:00');
+	`)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertNoErr(t, db.Close())
+
+	repo, err := repository.NewRepo(path)
+	testutil.AssertNoErr(t, err)
+
+	count, err := repo.GetWorkDayCount()
+	testutil.AssertNoErr(t, err)
+
+	if count != 1 {
+		t.Errorf("Expected the existing work day to survive migrating, got %d work days", count)
+	}
+}
@@ -0,0 +1,49 @@
+-- Databases created before migrations existed already have these tables, so
+-- every statement must be safe to run against them.
+CREATE TABLE IF NOT EXISTS work_days (
+	id 					INTEGER PRIMARY KEY,
+	date 				DATETIME NOT NULL,
+	length_mins INTEGER NOT NULL,
+	note 				TEXT,
+	created_at 	DATETIME NOT NULL,
+	updated_at 	DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_work_days_date on work_days(date);
+
+CREATE TABLE IF NOT EXISTS work_periods (
+	id					INTEGER PRIMARY KEY,
+	work_day_id	INTEGER NOT NULL,
+	start_at		DATETIME NOT NULL,
+	end_at			DATETIME,
+	note				TEXT,
+	created_at	DATETIME NOT NULL,
+	updated_at	DATETIME NOT NULL,
+
+	FOREIGN KEY(work_day_id) REFERENCES work_days(id)
+);
+
+CREATE TABLE IF NOT EXISTS balance_adjustments (
+	id					INTEGER PRIMARY KEY,
+	date				DATETIME NOT NULL,
+	amount_mins	INTEGER NOT NULL,
+	reason			TEXT NOT NULL,
+	created_at	DATETIME NOT NULL,
+	updated_at	DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS schedules (
+	id							INTEGER PRIMARY KEY,
+	effective_from	DATETIME NOT NULL,
+	monday_mins			INTEGER NOT NULL,
+	tuesday_mins		INTEGER NOT NULL,
+	wednesday_mins	INTEGER NOT NULL,
+	thursday_mins		INTEGER NOT NULL,
+	friday_mins			INTEGER NOT NULL,
+	saturday_mins		INTEGER NOT NULL,
+	sunday_mins			INTEGER NOT NULL,
+	created_at			DATETIME NOT NULL,
+	updated_at			DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_schedules_effective_from on schedules(effective_from);
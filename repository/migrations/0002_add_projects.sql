@@ -0,0 +1,11 @@
+CREATE TABLE projects (
+	id					INTEGER PRIMARY KEY,
+	name				TEXT NOT NULL,
+	archived_at	DATETIME,
+	created_at	DATETIME NOT NULL,
+	updated_at	DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_projects_name on projects(name);
+
+ALTER TABLE work_periods ADD COLUMN project_id INTEGER REFERENCES projects(id);
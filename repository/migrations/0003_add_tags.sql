@@ -0,0 +1,16 @@
+CREATE TABLE tags (
+	id					INTEGER PRIMARY KEY,
+	name				TEXT NOT NULL,
+	created_at	DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_tags_name on tags(name);
+
+CREATE TABLE work_period_tags (
+	work_period_id	INTEGER NOT NULL,
+	tag_id					INTEGER NOT NULL,
+
+	PRIMARY KEY(work_period_id, tag_id),
+	FOREIGN KEY(work_period_id) REFERENCES work_periods(id),
+	FOREIGN KEY(tag_id) REFERENCES tags(id)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE breaks (
+	id					INTEGER PRIMARY KEY,
+	work_day_id	INTEGER NOT NULL,
+	start_at		DATETIME NOT NULL,
+	end_at			DATETIME,
+	note				TEXT,
+	created_at	DATETIME NOT NULL,
+	updated_at	DATETIME NOT NULL,
+
+	FOREIGN KEY(work_day_id) REFERENCES work_days(id)
+);
+
+CREATE INDEX idx_breaks_work_day_id on breaks(work_day_id);
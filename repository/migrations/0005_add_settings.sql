@@ -0,0 +1,7 @@
+CREATE TABLE settings (
+	name	TEXT PRIMARY KEY,
+	value	TEXT NOT NULL
+);
+
+-- install_id tells this database apart from others, e.g. in calendar exports.
+INSERT INTO settings (name, value) VALUES ('install_id', lower(hex(randomblob(16))));
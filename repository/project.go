@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/robyparr/wh/model"
+)
+
+func (r *Repo) CreateProject(project model.Project) (model.Project, error) {
+	now := time.Now()
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	result, err := r.db.NamedExec(`
+		INSERT INTO projects (name, archived_at, created_at, updated_at)
+		VALUES (:name, :archived_at, :created_at, :updated_at)
+	`, project)
+
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	project.Id = int(id)
+	return project, nil
+}
+
+func (r *Repo) GetProjectByName(name string) (model.Project, error) {
+	var project model.Project
+	if err := r.db.Get(&project, "SELECT * FROM projects WHERE name = ?", name); err != nil {
+		if err == sql.ErrNoRows {
+			return model.Project{}, nil
+		}
+
+		return model.Project{}, err
+	}
+
+	return project, nil
+}
+
+func (r *Repo) GetProjects(includeArchived bool) ([]model.Project, error) {
+	query := "SELECT * FROM projects WHERE archived_at IS NULL ORDER BY name"
+	if includeArchived {
+		query = "SELECT * FROM projects ORDER BY name"
+	}
+
+	var projects []model.Project
+	if err := r.db.Select(&projects, query); err != nil {
+		return []model.Project{}, err
+	}
+
+	return projects, nil
+}
+
+func (r *Repo) UpdateProject(project model.Project) (model.Project, error) {
+	project.UpdatedAt = time.Now()
+
+	result, err := r.db.NamedExec(`
+		UPDATE projects
+		SET name = :name,
+				archived_at = :archived_at,
+				updated_at = :updated_at
+		WHERE id = :id
+	`, project)
+
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	if rowsAffected == 0 {
+		return model.Project{}, errNoUpdatedRows
+	}
+
+	return project, nil
+}
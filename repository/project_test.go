@@ -0,0 +1,118 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestCreateProject(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	got, err := repo.CreateProject(model.NewProject("acme"))
+	testutil.AssertNoErr(t, err)
+
+	want := model.Project{Id: 1, Name: "acme", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	testutil.AssertEqualStructs(t, got, want)
+
+	if _, err := repo.CreateProject(model.NewProject("acme")); err == nil {
+		t.Error("Expected an error creating a duplicate project but got none")
+	}
+}
+
+func TestGetProjectByName(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	t.Run("No results", func(t *testing.T) {
+		got, err := repo.GetProjectByName("acme")
+		testutil.AssertNoErr(t, err)
+
+		if got.Id != 0 {
+			t.Errorf("Expected an empty project, got %+v", got)
+		}
+	})
+
+	t.Run("Found a project", func(t *testing.T) {
+		want, err := repo.CreateProject(model.NewProject("acme"))
+		testutil.AssertNoErr(t, err)
+
+		got, err := repo.GetProjectByName("acme")
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, got, want)
+	})
+}
+
+func TestGetProjects(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	active, err := repo.CreateProject(model.NewProject("beta"))
+	testutil.AssertNoErr(t, err)
+
+	archived := model.NewProject("alpha")
+	archived.Archive()
+	archived, err = repo.CreateProject(archived)
+	testutil.AssertNoErr(t, err)
+
+	t.Run("active only", func(t *testing.T) {
+		got, err := repo.GetProjects(false)
+		testutil.AssertNoErr(t, err)
+
+		if len(got) != 1 {
+			t.Fatalf("Expected 1 project, got %d", len(got))
+		}
+		testutil.AssertEqualStructs(t, got[0], active)
+	})
+
+	t.Run("including archived", func(t *testing.T) {
+		got, err := repo.GetProjects(true)
+		testutil.AssertNoErr(t, err)
+
+		if len(got) != 2 {
+			t.Fatalf("Expected 2 projects, got %d", len(got))
+		}
+		testutil.AssertEqualStructs(t, got[0], archived)
+		testutil.AssertEqualStructs(t, got[1], active)
+	})
+}
+
+func TestUpdateProject(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	project, err := repo.CreateProject(model.NewProject("acme"))
+	testutil.AssertNoErr(t, err)
+
+	project.Archive()
+	got, err := repo.UpdateProject(project)
+	testutil.AssertNoErr(t, err)
+
+	gotFromDb, err := repo.GetProjectByName("acme")
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEqualStructs(t, gotFromDb, got)
+
+	if !gotFromDb.IsArchived() {
+		t.Error("Expected project to be archived")
+	}
+}
+
+func TestWorkPeriodProject(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	project, err := repo.CreateProject(model.NewProject("acme"))
+	testutil.AssertNoErr(t, err)
+
+	period := model.NewWorkPeriod(workDay)
+	period.SetProject(project)
+	period, err = repo.CreateWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	got, err := repo.GetWorkPeriodById(period.Id)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEqualStructs(t, got, period)
+
+	if got.ProjectId.Int64 != int64(project.Id) {
+		t.Errorf("got project ID %d, want %d", got.ProjectId.Int64, project.Id)
+	}
+}
@@ -3,74 +3,133 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/robyparr/wh/model"
 )
 
-const schema string = `
-	CREATE TABLE IF NOT EXISTS work_days (
-		id 					INTEGER PRIMARY KEY,
-		date 				DATETIME NOT NULL,
-		length_mins INTEGER NOT NULL,
-		note 				TEXT,
-		created_at 	DATETIME NOT NULL,
-		updated_at 	DATETIME NOT NULL
-	);
-
-	CREATE UNIQUE INDEX IF NOT EXISTS idx_work_days_date on work_days(date);
-
-	CREATE TABLE IF NOT EXISTS work_periods (
-		id					INTEGER PRIMARY KEY,
-		work_day_id	INTEGER NOT NULL,
-		start_at		DATETIME NOT NULL,
-		end_at			DATETIME,
-		note				TEXT,
-		created_at	DATETIME NOT NULL,
-		updated_at	DATETIME NOT NULL,
-
-		FOREIGN KEY(work_day_id) REFERENCES work_days(id)
-	);
-`
-
-const DefaultDatabasePath string = "./db.sqlite"
+// LegacyDatabasePath is where older versions of wh kept their database,
+// relative to the working directory.
+const LegacyDatabasePath string = "./db.sqlite"
+
+// DefaultDatabasePath returns $XDG_DATA_HOME/wh/wh.sqlite, falling back to
+// ~/.local/share when XDG_DATA_HOME isn't set.
+func DefaultDatabasePath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dataHome, "wh", "wh.sqlite"), nil
+}
+
+// IsDatabase reports whether the file at path is a wh database, rather than
+// any other file that happens to have the same name.
+func IsDatabase(path string) bool {
+	db, err := sqlx.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return false
+	}
+	defer db.Close()
+
+	var count int
+	err = db.Get(&count, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'work_days'")
+	return err == nil && count > 0
+}
 
 var errNoUpdatedRows error = errors.New("no rows were updated")
+var errNoDeletedRows error = errors.New("no rows were deleted")
 
-func NewRepo(filepath string) (*Repo, error) {
-	db, err := sqlx.Open("sqlite3", filepath)
+// NewRepo opens the database at path and applies any pending migrations.
+func NewRepo(path string) (*Repo, error) {
+	repo, err := Open(path)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = db.Exec(schema)
+	if _, err := repo.Migrate(); err != nil {
+		return nil, fmt.Errorf("error migrating database: %v", err)
+	}
+
+	return repo, nil
+}
+
+// Open opens the database at path without migrating it.
+func Open(path string) (*Repo, error) {
+	db, err := sqlx.Open("sqlite3", path)
 	if err != nil {
 		return nil, err
 	}
 
+	// SQLite only supports a single writer, and each connection to an
+	// in-memory database gets its own database, so stick to one connection.
+	db.SetMaxOpenConns(1)
+
 	_, err = db.Exec("PRAGMA foreign_keys = ON;")
 	if err != nil {
 		return nil, err
 	}
 
-	return &Repo{
-		db: db,
-	}, nil
+	return &Repo{db: db}, nil
 }
 
 type Repo struct {
 	db *sqlx.DB
 }
 
+func (r *Repo) withTx(fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *Repo) CreateWorkDay(workDay model.WorkDay) (model.WorkDay, error) {
+	err := r.withTx(func(tx *sqlx.Tx) error {
+		var err error
+		workDay, err = createWorkDay(tx, workDay)
+		return err
+	})
+
+	if err != nil {
+		return model.WorkDay{}, err
+	}
+
+	return workDay, nil
+}
+
+// createWorkDay inserts the work day with a new ID and fresh timestamps.
+func createWorkDay(tx *sqlx.Tx, workDay model.WorkDay) (model.WorkDay, error) {
 	now := time.Now()
+	workDay.Id = 0
 	workDay.CreatedAt = now
 	workDay.UpdatedAt = now
 
-	result, err := r.db.NamedExec(`
-		INSERT INTO work_days (date, length_mins, note, created_at, updated_at)
-		VALUES (:date, :length_mins, :note, :created_at, :updated_at)
+	return insertWorkDay(tx, workDay)
+}
+
+// insertWorkDay inserts the work day as is, keeping its timestamps and its ID
+// when it has one.
+func insertWorkDay(tx *sqlx.Tx, workDay model.WorkDay) (model.WorkDay, error) {
+	result, err := tx.NamedExec(`
+		INSERT INTO work_days (id, date, length_mins, note, created_at, updated_at)
+		VALUES (NULLIF(:id, 0), :date, :length_mins, :note, :created_at, :updated_at)
 	`, workDay)
 
 	if err != nil {
@@ -87,6 +146,39 @@ func (r *Repo) CreateWorkDay(workDay model.WorkDay) (model.WorkDay, error) {
 	return workDay, nil
 }
 
+// LogWorkDay creates the work periods on the work day in a single transaction,
+// first creating the work day if it doesn't have an ID yet.
+func (r *Repo) LogWorkDay(workDay model.WorkDay, periods []model.WorkPeriod) (model.WorkDay, []model.WorkPeriod, error) {
+	created := make([]model.WorkPeriod, len(periods))
+	err := r.withTx(func(tx *sqlx.Tx) error {
+		if workDay.Id == 0 {
+			var err error
+			workDay, err = createWorkDay(tx, workDay)
+			if err != nil {
+				return err
+			}
+		}
+
+		for i, period := range periods {
+			period.WorkDayId = workDay.Id
+
+			var err error
+			created[i], err = createWorkPeriod(tx, period)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return model.WorkDay{}, nil, err
+	}
+
+	return workDay, created, nil
+}
+
 func (r *Repo) GetWorkDayByDate(date time.Time) (model.WorkDay, error) {
 	var workDay model.WorkDay
 	if err := r.db.Get(&workDay, "SELECT * FROM work_days WHERE date = ?", date); err != nil {
@@ -100,6 +192,137 @@ func (r *Repo) GetWorkDayByDate(date time.Time) (model.WorkDay, error) {
 	return workDay, nil
 }
 
+func (r *Repo) GetWorkDayById(id int) (model.WorkDay, error) {
+	var workDay model.WorkDay
+	if err := r.db.Get(&workDay, "SELECT * FROM work_days WHERE id = ?", id); err != nil {
+		if err == sql.ErrNoRows {
+			return model.WorkDay{}, nil
+		}
+
+		return model.WorkDay{}, err
+	}
+
+	return workDay, nil
+}
+
+func (r *Repo) GetWorkDaysInRange(from time.Time, to time.Time) ([]model.WorkDay, error) {
+	var workDays []model.WorkDay
+	if err := r.db.Select(&workDays, "SELECT * FROM work_days WHERE date BETWEEN ? AND ? ORDER BY date", from, to); err != nil {
+		return []model.WorkDay{}, err
+	}
+
+	var periods []model.WorkPeriod
+	if err := r.db.Select(&periods, `
+		SELECT work_periods.*
+		FROM work_periods
+		INNER JOIN work_days ON work_days.id = work_periods.work_day_id
+		WHERE work_days.date BETWEEN ? AND ?
+		ORDER BY work_periods.start_at
+	`, from, to); err != nil {
+		return []model.WorkDay{}, err
+	}
+
+	if err := r.loadWorkPeriodTags(periods); err != nil {
+		return []model.WorkDay{}, err
+	}
+
+	periodsByDay := make(map[int][]model.WorkPeriod)
+	for _, wp := range periods {
+		periodsByDay[wp.WorkDayId] = append(periodsByDay[wp.WorkDayId], wp)
+	}
+
+	for i := range workDays {
+		workDays[i].SetWorkPeriods(periodsByDay[workDays[i].Id])
+	}
+
+	if err := r.loadBreaks(workDays, from, to); err != nil {
+		return []model.WorkDay{}, err
+	}
+
+	return workDays, nil
+}
+
+// workDayPageSize is how many work days EachWorkDayInRange loads at a time.
+const workDayPageSize int = 100
+
+// EachWorkDayInRange calls fn with each work day in the range, in date order
+// and with its work periods and breaks set. Work days are loaded a page at a
+// time so large ranges never have to fit in memory. Iteration stops at the
+// first error fn returns.
+func (r *Repo) EachWorkDayInRange(from time.Time, to time.Time, fn func(model.WorkDay) error) error {
+	for {
+		var dates []time.Time
+		if err := r.db.Select(&dates, "SELECT date FROM work_days WHERE date BETWEEN ? AND ? ORDER BY date LIMIT ?", from, to, workDayPageSize); err != nil {
+			return err
+		}
+
+		if len(dates) == 0 {
+			return nil
+		}
+
+		workDays, err := r.GetWorkDaysInRange(dates[0], dates[len(dates)-1])
+		if err != nil {
+			return err
+		}
+
+		for _, workDay := range workDays {
+			if err := fn(workDay); err != nil {
+				return err
+			}
+		}
+
+		if len(dates) < workDayPageSize {
+			return nil
+		}
+
+		from = dates[len(dates)-1].AddDate(0, 0, 1)
+	}
+}
+
+func (r *Repo) UpdateWorkDay(workDay model.WorkDay) (model.WorkDay, error) {
+	workDay.UpdatedAt = time.Now()
+
+	result, err := r.db.NamedExec(`
+		UPDATE work_days
+		SET length_mins = :length_mins,
+				updated_at = :updated_at,
+				note = :note
+		WHERE id = :id
+	`, workDay)
+
+	if err != nil {
+		return model.WorkDay{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.WorkDay{}, err
+	}
+
+	if rowsAffected == 0 {
+		return model.WorkDay{}, errNoUpdatedRows
+	}
+
+	return workDay, nil
+}
+
+// DeleteWorkDay deletes the work day along with all of its work periods and
+// breaks.
+func (r *Repo) DeleteWorkDay(workDay model.WorkDay) error {
+	return r.withTx(func(tx *sqlx.Tx) error {
+		if err := deleteWorkDayEntries(tx, workDay.Id); err != nil {
+			return err
+		}
+
+		result, err := tx.Exec("DELETE FROM work_days WHERE id = ?", workDay.Id)
+		if err != nil {
+			return err
+		}
+
+		return checkRowsDeleted(result)
+	})
+}
+
 func (r *Repo) GetWorkDayCount() (int, error) {
 	var count int
 	if err := r.db.Get(&count, "SELECT COUNT(*) FROM work_days;"); err != nil {
@@ -110,13 +333,35 @@ func (r *Repo) GetWorkDayCount() (int, error) {
 }
 
 func (r *Repo) CreateWorkPeriod(period model.WorkPeriod) (model.WorkPeriod, error) {
+	err := r.withTx(func(tx *sqlx.Tx) error {
+		var err error
+		period, err = createWorkPeriod(tx, period)
+		return err
+	})
+
+	if err != nil {
+		return model.WorkPeriod{}, err
+	}
+
+	return period, nil
+}
+
+// createWorkPeriod inserts the work period with a new ID and fresh timestamps.
+func createWorkPeriod(tx *sqlx.Tx, period model.WorkPeriod) (model.WorkPeriod, error) {
 	now := time.Now()
+	period.Id = 0
 	period.CreatedAt = now
 	period.UpdatedAt = now
 
-	result, err := r.db.NamedExec(`
-		INSERT INTO work_periods (work_day_id, start_at, end_at, created_at, updated_at, note)
-		VALUES (:work_day_id, :start_at, :end_at, :created_at, :updated_at, :note)
+	return insertWorkPeriod(tx, period)
+}
+
+// insertWorkPeriod inserts the work period and its tags as is, keeping its
+// timestamps and its ID when it has one.
+func insertWorkPeriod(tx *sqlx.Tx, period model.WorkPeriod) (model.WorkPeriod, error) {
+	result, err := tx.NamedExec(`
+		INSERT INTO work_periods (id, work_day_id, start_at, end_at, created_at, updated_at, note, project_id)
+		VALUES (NULLIF(:id, 0), :work_day_id, :start_at, :end_at, :created_at, :updated_at, :note, :project_id)
 	`, period)
 
 	if err != nil {
@@ -125,10 +370,14 @@ func (r *Repo) CreateWorkPeriod(period model.WorkPeriod) (model.WorkPeriod, erro
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return model.WorkPeriod{}, nil
+		return model.WorkPeriod{}, err
 	}
 
 	period.Id = int(id)
+	if err := setWorkPeriodTags(tx, period); err != nil {
+		return model.WorkPeriod{}, err
+	}
+
 	return period, nil
 }
 
@@ -138,12 +387,34 @@ func (r *Repo) GetWorkPeriods(workDay model.WorkDay) ([]model.WorkPeriod, error)
 		return []model.WorkPeriod{}, err
 	}
 
+	if err := r.loadWorkPeriodTags(periods); err != nil {
+		return []model.WorkPeriod{}, err
+	}
+
 	return periods, nil
 }
 
-func (r *Repo) GetOpenWorkPeriod(workDay model.WorkDay) (model.WorkPeriod, error) {
+func (r *Repo) GetWorkPeriodById(id int) (model.WorkPeriod, error) {
+	return r.getWorkPeriod("SELECT * FROM work_periods WHERE id = ?", id)
+}
+
+// GetOpenWorkPeriod returns the work period that hasn't ended yet, whichever
+// work day it started on.
+func (r *Repo) GetOpenWorkPeriod() (model.WorkPeriod, error) {
+	return r.getWorkPeriod("SELECT * FROM work_periods WHERE end_at IS NULL ORDER BY start_at DESC LIMIT 1;")
+}
+
+// GetLastClosedWorkPeriod returns the work period that ended most recently, on
+// any work day.
+func (r *Repo) GetLastClosedWorkPeriod() (model.WorkPeriod, error) {
+	return r.getWorkPeriod("SELECT * FROM work_periods WHERE end_at IS NOT NULL ORDER BY end_at DESC, id DESC LIMIT 1;")
+}
+
+// getWorkPeriod loads the first work period matching the query along with its
+// tags, or an empty work period if none match.
+func (r *Repo) getWorkPeriod(query string, args ...any) (model.WorkPeriod, error) {
 	var period model.WorkPeriod
-	if err := r.db.Get(&period, "SELECT * FROM work_periods WHERE work_day_id = ? AND end_at IS NULL;", workDay.Id); err != nil {
+	if err := r.db.Get(&period, query, args...); err != nil {
 		if err == sql.ErrNoRows {
 			return model.WorkPeriod{}, nil
 		}
@@ -151,17 +422,39 @@ func (r *Repo) GetOpenWorkPeriod(workDay model.WorkDay) (model.WorkPeriod, error
 		return model.WorkPeriod{}, err
 	}
 
-	return period, nil
+	periods := []model.WorkPeriod{period}
+	if err := r.loadWorkPeriodTags(periods); err != nil {
+		return model.WorkPeriod{}, err
+	}
+
+	return periods[0], nil
 }
 
+// UpdateWorkPeriod saves the work period, including replacing its tags.
 func (r *Repo) UpdateWorkPeriod(workPeriod model.WorkPeriod) (model.WorkPeriod, error) {
+	err := r.withTx(func(tx *sqlx.Tx) error {
+		var err error
+		workPeriod, err = updateWorkPeriod(tx, workPeriod)
+		return err
+	})
+
+	if err != nil {
+		return model.WorkPeriod{}, err
+	}
+
+	return workPeriod, nil
+}
+
+func updateWorkPeriod(tx *sqlx.Tx, workPeriod model.WorkPeriod) (model.WorkPeriod, error) {
 	workPeriod.UpdatedAt = time.Now()
 
-	result, err := r.db.NamedExec(`
+	result, err := tx.NamedExec(`
 		UPDATE work_periods
-		SET end_at = :end_at,
+		SET start_at = :start_at,
+				end_at = :end_at,
 				updated_at = :updated_at,
-				note = :note
+				note = :note,
+				project_id = :project_id
 		WHERE id = :id
 	`, workPeriod)
 
@@ -178,5 +471,67 @@ func (r *Repo) UpdateWorkPeriod(workPeriod model.WorkPeriod) (model.WorkPeriod,
 		return model.WorkPeriod{}, errNoUpdatedRows
 	}
 
+	if err := setWorkPeriodTags(tx, workPeriod); err != nil {
+		return model.WorkPeriod{}, err
+	}
+
 	return workPeriod, nil
 }
+
+// SwitchWorkPeriod closes the open work period and creates the next one on
+// the work day in a single transaction, so there's never a gap or a
+// half-finished switch. The work day is created first if it doesn't have an
+// ID yet.
+func (r *Repo) SwitchWorkPeriod(open model.WorkPeriod, workDay model.WorkDay, next model.WorkPeriod) (model.WorkDay, model.WorkPeriod, error) {
+	err := r.withTx(func(tx *sqlx.Tx) error {
+		if _, err := updateWorkPeriod(tx, open); err != nil {
+			return err
+		}
+
+		var err error
+		if workDay.Id == 0 {
+			workDay, err = createWorkDay(tx, workDay)
+			if err != nil {
+				return err
+			}
+		}
+
+		next.WorkDayId = workDay.Id
+		next, err = createWorkPeriod(tx, next)
+		return err
+	})
+
+	if err != nil {
+		return model.WorkDay{}, model.WorkPeriod{}, err
+	}
+
+	return workDay, next, nil
+}
+
+func (r *Repo) DeleteWorkPeriod(workPeriod model.WorkPeriod) error {
+	return r.withTx(func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec("DELETE FROM work_period_tags WHERE work_period_id = ?", workPeriod.Id); err != nil {
+			return err
+		}
+
+		result, err := tx.Exec("DELETE FROM work_periods WHERE id = ?", workPeriod.Id)
+		if err != nil {
+			return err
+		}
+
+		return checkRowsDeleted(result)
+	})
+}
+
+func checkRowsDeleted(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errNoDeletedRows
+	}
+
+	return nil
+}
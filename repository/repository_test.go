@@ -2,11 +2,14 @@ package repository_test
 
 import (
 	"database/sql"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/repository"
 	"github.com/robyparr/wh/util"
 	"github.com/robyparr/wh/util/testutil"
 
@@ -37,6 +40,50 @@ func TestCreateWorkDay(t *testing.T) {
 	testutil.AssertWorkDay(t, gotFromDb, want)
 }
 
+func TestLogWorkDay(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 14, 0, 0, 0, 0, time.Local)
+
+	period := func(startHour int, endHour int) model.WorkPeriod {
+		wp := model.WorkPeriod{StartAt: date.Add(time.Duration(startHour) * time.Hour)}
+		wp.SetEndAt(date.Add(time.Duration(endHour) * time.Hour))
+		return wp
+	}
+
+	gotDay, gotPeriods, err := repo.LogWorkDay(model.NewWorkDay(date), []model.WorkPeriod{period(9, 12), period(13, 17)})
+	testutil.AssertNoErr(t, err)
+
+	if gotDay.Id == 0 {
+		t.Fatal("Expected the work day to be created")
+	}
+
+	periods, err := repo.GetWorkPeriods(gotDay)
+	testutil.AssertNoErr(t, err)
+	if len(periods) != 2 {
+		t.Fatalf("got %d work periods, want 2", len(periods))
+	}
+
+	for i := range periods {
+		testutil.AssertEqualStructs(t, periods[i], gotPeriods[i])
+	}
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		invalid := period(18, 19)
+		invalid.ProjectId = sql.NullInt64{Valid: true, Int64: 999}
+
+		_, _, err := repo.LogWorkDay(model.NewWorkDay(date.AddDate(0, 0, 1)), []model.WorkPeriod{period(9, 12), invalid})
+		if err == nil {
+			t.Fatal("Expected an error but got none")
+		}
+
+		count, err := repo.GetWorkDayCount()
+		testutil.AssertNoErr(t, err)
+		if count != 1 {
+			t.Errorf("got %d work days, want 1", count)
+		}
+	})
+}
+
 func TestGetWorkDayByDate(t *testing.T) {
 	repo := testutil.NewRepo(t)
 	date := time.Date(2023, 8, 11, 0, 0, 0, 0, time.Local)
@@ -160,7 +207,7 @@ func TestGetOpenWorkPeriod(t *testing.T) {
 	testutil.AssertNoErr(t, err)
 
 	t.Run("no work periods", func(t *testing.T) {
-		gotPeriod, err := repo.GetOpenWorkPeriod(workDay)
+		gotPeriod, err := repo.GetOpenWorkPeriod()
 		testutil.AssertNoErr(t, err)
 
 		if gotPeriod.Id != 0 {
@@ -175,7 +222,7 @@ func TestGetOpenWorkPeriod(t *testing.T) {
 		_, err := repo.CreateWorkPeriod(closedPeriod)
 		testutil.AssertNoErr(t, err)
 
-		gotPeriod, err := repo.GetOpenWorkPeriod(workDay)
+		gotPeriod, err := repo.GetOpenWorkPeriod()
 		testutil.AssertNoErr(t, err)
 
 		if gotPeriod.Id != 0 {
@@ -187,13 +234,55 @@ func TestGetOpenWorkPeriod(t *testing.T) {
 		wantPeriod, err := repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
 		testutil.AssertNoErr(t, err)
 
-		gotPeriod, err := repo.GetOpenWorkPeriod(workDay)
+		gotPeriod, err := repo.GetOpenWorkPeriod()
 		testutil.AssertNoErr(t, err)
 
 		testutil.AssertEqualStructs(t, gotPeriod, wantPeriod)
 	})
 }
 
+func TestGetLastClosedWorkPeriod(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	t.Run("no work periods", func(t *testing.T) {
+		got, err := repo.GetLastClosedWorkPeriod()
+		testutil.AssertNoErr(t, err)
+
+		if got.Id != 0 {
+			t.Errorf("Expected an empty work period, got %+v", got)
+		}
+	})
+
+	t.Run("latest end across work days", func(t *testing.T) {
+		today := util.TodayAtMidnight()
+		yesterday, err := repo.CreateWorkDay(model.NewWorkDay(today.AddDate(0, 0, -1)))
+		testutil.AssertNoErr(t, err)
+
+		workDay, err := repo.CreateWorkDay(model.NewWorkDay(today))
+		testutil.AssertNoErr(t, err)
+
+		want := model.NewWorkPeriod(yesterday)
+		want.StartAt = yesterday.Date.Add(22 * time.Hour)
+		want.SetEndAt(yesterday.Date.Add(23 * time.Hour))
+		want.AddTags("oncall")
+		want, err = repo.CreateWorkPeriod(want)
+		testutil.AssertNoErr(t, err)
+
+		earlier := model.NewWorkPeriod(yesterday)
+		earlier.StartAt = yesterday.Date.Add(9 * time.Hour)
+		earlier.SetEndAt(yesterday.Date.Add(10 * time.Hour))
+		_, err = repo.CreateWorkPeriod(earlier)
+		testutil.AssertNoErr(t, err)
+
+		_, err = repo.CreateWorkPeriod(model.WorkPeriod{WorkDayId: workDay.Id, StartAt: today})
+		testutil.AssertNoErr(t, err)
+
+		got, err := repo.GetLastClosedWorkPeriod()
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, got, want)
+	})
+}
+
 func TestUpdateWorkPeriod(t *testing.T) {
 	repo := testutil.NewRepo(t)
 	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
@@ -202,6 +291,7 @@ func TestUpdateWorkPeriod(t *testing.T) {
 	period, err := repo.CreateWorkPeriod(model.WorkPeriod{WorkDayId: workDay.Id, StartAt: util.TodayAtMidnight()})
 	testutil.AssertNoErr(t, err)
 
+	period.StartAt = util.TodayAtMidnight().Add(time.Hour)
 	period.SetEndAt(time.Now())
 	period.SetNote("Hello!")
 	updatedAtBefore := period.UpdatedAt
@@ -212,4 +302,333 @@ func TestUpdateWorkPeriod(t *testing.T) {
 	if !gotPeriod.UpdatedAt.After(updatedAtBefore) {
 		t.Error("Expected UpdatedAt to have changed but it didn't.")
 	}
+
+	gotFromDb, err := repo.GetWorkPeriodById(period.Id)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEqualStructs(t, gotFromDb, gotPeriod)
+}
+
+func TestSwitchWorkPeriod(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	open, err := repo.CreateWorkPeriod(model.WorkPeriod{WorkDayId: workDay.Id, StartAt: util.TodayAtMidnight()})
+	testutil.AssertNoErr(t, err)
+
+	switchAt := util.TodayAtMidnight().Add(time.Hour)
+	open.SetEndAt(switchAt)
+
+	t.Run("rolls back when the new work period fails", func(t *testing.T) {
+		_, _, err := repo.SwitchWorkPeriod(open, model.WorkDay{Id: 999}, model.WorkPeriod{StartAt: switchAt})
+		if err == nil {
+			t.Fatal("Expected an error but got none")
+		}
+
+		got, err := repo.GetWorkPeriodById(open.Id)
+		testutil.AssertNoErr(t, err)
+		if got.EndAt.Valid {
+			t.Error("Expected the open work period to still be open")
+		}
+	})
+
+	t.Run("rolls back the new work day when the new work period fails", func(t *testing.T) {
+		next := model.WorkPeriod{StartAt: switchAt, ProjectId: sql.NullInt64{Valid: true, Int64: 999}}
+		_, _, err := repo.SwitchWorkPeriod(open, model.NewWorkDay(util.TodayAtMidnight().AddDate(0, 0, 1)), next)
+		if err == nil {
+			t.Fatal("Expected an error but got none")
+		}
+
+		count, err := repo.GetWorkDayCount()
+		testutil.AssertNoErr(t, err)
+		if count != 1 {
+			t.Errorf("Expected 1 work day, got %d", count)
+		}
+	})
+
+	t.Run("creates the new work day", func(t *testing.T) {
+		tomorrow := util.TodayAtMidnight().AddDate(0, 0, 1)
+		nextDay, next, err := repo.SwitchWorkPeriod(open, model.NewWorkDay(tomorrow), model.WorkPeriod{StartAt: switchAt})
+		testutil.AssertNoErr(t, err)
+
+		if nextDay.Id == 0 || next.WorkDayId != nextDay.Id {
+			t.Errorf("Expected the work period on the new work day, got work day #%d and %+v", nextDay.Id, next)
+		}
+
+		testutil.AssertNoErr(t, repo.DeleteWorkPeriod(next))
+		testutil.AssertNoErr(t, repo.DeleteWorkDay(nextDay))
+	})
+
+	t.Run("closes and creates", func(t *testing.T) {
+		_, next, err := repo.SwitchWorkPeriod(open, workDay, model.WorkPeriod{StartAt: switchAt})
+		testutil.AssertNoErr(t, err)
+
+		got, err := repo.GetWorkPeriodById(open.Id)
+		testutil.AssertNoErr(t, err)
+		if !got.EndAt.Valid || !got.EndAt.Time.Equal(switchAt) {
+			t.Errorf("got end %v, want %v", got.EndAt, switchAt)
+		}
+
+		gotNext, err := repo.GetWorkPeriodById(next.Id)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, gotNext, next)
+
+		if !gotNext.StartAt.Equal(switchAt) {
+			t.Errorf("got start %v, want %v", gotNext.StartAt, switchAt)
+		}
+	})
+}
+
+func TestGetWorkDaysInRange(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	dates := []time.Time{
+		time.Date(2023, 8, 31, 0, 0, 0, 0, time.Local),
+		time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2023, 9, 2, 0, 0, 0, 0, time.Local),
+		time.Date(2023, 9, 3, 0, 0, 0, 0, time.Local),
+	}
+	var workDays []model.WorkDay
+	for _, date := range dates {
+		wd, err := repo.CreateWorkDay(model.NewWorkDay(date))
+		testutil.AssertNoErr(t, err)
+
+		wp := model.WorkPeriod{WorkDayId: wd.Id, StartAt: date.Add(9 * time.Hour)}
+		wp.SetEndAt(date.Add(10 * time.Hour))
+		_, err = repo.CreateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+
+		workDays = append(workDays, wd)
+	}
+
+	got, err := repo.GetWorkDaysInRange(dates[1], dates[2])
+	testutil.AssertNoErr(t, err)
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 work days, got %d", len(got))
+	}
+
+	testutil.AssertEqualStructs(t, got[0], workDays[1])
+	testutil.AssertEqualStructs(t, got[1], workDays[2])
+
+	for _, wd := range got {
+		if wd.TimeWorked() != time.Hour {
+			t.Errorf("Expected work day %d to have 1h worked, got %v", wd.Id, wd.TimeWorked())
+		}
+	}
+}
+
+func TestGetWorkDayById(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	t.Run("No results", func(t *testing.T) {
+		got, err := repo.GetWorkDayById(1)
+		testutil.AssertNoErr(t, err)
+
+		if got.Id != 0 {
+			t.Errorf("Expected empty workday, got %+v\n", got)
+		}
+	})
+
+	t.Run("Found a work day", func(t *testing.T) {
+		want, err := repo.CreateWorkDay(model.NewWorkDayToday())
+		testutil.AssertNoErr(t, err)
+
+		got, err := repo.GetWorkDayById(want.Id)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertWorkDay(t, got, want)
+	})
+}
+
+func TestEachWorkDayInRange(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+
+	// Enough work days to need more than one page.
+	const dayCount = 250
+	for i := 0; i < dayCount; i++ {
+		wd, err := repo.CreateWorkDay(model.NewWorkDay(start.AddDate(0, 0, i)))
+		testutil.AssertNoErr(t, err)
+
+		_, err = repo.CreateWorkPeriod(model.WorkPeriod{WorkDayId: wd.Id, StartAt: wd.Date.Add(9 * time.Hour)})
+		testutil.AssertNoErr(t, err)
+	}
+
+	var got []time.Time
+	err := repo.EachWorkDayInRange(start.AddDate(0, 0, 1), start.AddDate(0, 0, dayCount), func(wd model.WorkDay) error {
+		if len(wd.WorkPeriods()) != 1 {
+			t.Errorf("%v: got %d work periods, want 1", wd.Date, len(wd.WorkPeriods()))
+		}
+
+		got = append(got, wd.Date)
+		return nil
+	})
+	testutil.AssertNoErr(t, err)
+
+	if len(got) != dayCount-1 {
+		t.Fatalf("got %d work days, want %d", len(got), dayCount-1)
+	}
+
+	for i, date := range got {
+		if want := start.AddDate(0, 0, i+1); !date.Equal(want) {
+			t.Errorf("work day %d: got %v, want %v", i, date, want)
+		}
+	}
+}
+
+func TestUpdateWorkDay(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	workDay.LengthMins = 6 * 60
+	workDay.SetNote("Hello!")
+	updatedAtBefore := workDay.UpdatedAt
+
+	gotWorkDay, err := repo.UpdateWorkDay(workDay)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEqualStructs(t, gotWorkDay, workDay)
+	if !gotWorkDay.UpdatedAt.After(updatedAtBefore) {
+		t.Error("Expected UpdatedAt to have changed but it didn't.")
+	}
+
+	gotFromDb, err := repo.GetWorkDayById(workDay.Id)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEqualStructs(t, gotFromDb, gotWorkDay)
+}
+
+func TestGetWorkPeriodById(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	t.Run("No results", func(t *testing.T) {
+		got, err := repo.GetWorkPeriodById(1)
+		testutil.AssertNoErr(t, err)
+
+		if got.Id != 0 {
+			t.Errorf("Expected an empty work period, got %+v", got)
+		}
+	})
+
+	t.Run("Found a work period", func(t *testing.T) {
+		want, err := repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
+		testutil.AssertNoErr(t, err)
+
+		got, err := repo.GetWorkPeriodById(want.Id)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEqualStructs(t, got, want)
+	})
+}
+
+func TestDeleteWorkDay(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	_, err = repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
+	testutil.AssertNoErr(t, err)
+
+	err = repo.DeleteWorkDay(workDay)
+	testutil.AssertNoErr(t, err)
+
+	gotWorkDay, err := repo.GetWorkDayById(workDay.Id)
+	testutil.AssertNoErr(t, err)
+	if gotWorkDay.Id != 0 {
+		t.Errorf("Expected work day to be deleted, got %+v", gotWorkDay)
+	}
+
+	gotPeriods, err := repo.GetWorkPeriods(workDay)
+	testutil.AssertNoErr(t, err)
+	if len(gotPeriods) != 0 {
+		t.Errorf("Expected work periods to be deleted, got %d", len(gotPeriods))
+	}
+
+	if err := repo.DeleteWorkDay(workDay); err == nil {
+		t.Error("Expected an error deleting a missing work day but got none")
+	}
+}
+
+func TestDeleteWorkPeriod(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	workDay, err := repo.CreateWorkDay(model.NewWorkDayToday())
+	testutil.AssertNoErr(t, err)
+
+	period, err := repo.CreateWorkPeriod(model.NewWorkPeriod(workDay))
+	testutil.AssertNoErr(t, err)
+
+	err = repo.DeleteWorkPeriod(period)
+	testutil.AssertNoErr(t, err)
+
+	gotPeriod, err := repo.GetWorkPeriodById(period.Id)
+	testutil.AssertNoErr(t, err)
+	if gotPeriod.Id != 0 {
+		t.Errorf("Expected work period to be deleted, got %+v", gotPeriod)
+	}
+
+	if err := repo.DeleteWorkPeriod(period); err == nil {
+		t.Error("Expected an error deleting a missing work period but got none")
+	}
+}
+
+func TestDefaultDatabasePath(t *testing.T) {
+	t.Run("XDG_DATA_HOME set", func(t *testing.T) {
+		t.Setenv("XDG_DATA_HOME", "/data")
+
+		got, err := repository.DefaultDatabasePath()
+		testutil.AssertNoErr(t, err)
+
+		if want := "/data/wh/wh.sqlite"; got != want {
+			t.Errorf("got '%s', want '%s'", got, want)
+		}
+	})
+
+	t.Run("XDG_DATA_HOME unset", func(t *testing.T) {
+		t.Setenv("XDG_DATA_HOME", "")
+		t.Setenv("HOME", "/home/me")
+
+		got, err := repository.DefaultDatabasePath()
+		testutil.AssertNoErr(t, err)
+
+		if want := "/home/me/.local/share/wh/wh.sqlite"; got != want {
+			t.Errorf("got '%s', want '%s'", got, want)
+		}
+	})
+}
+
+func TestIsDatabase(t *testing.T) {
+	dir := t.TempDir()
+
+	database := filepath.Join(dir, "wh.sqlite")
+	_, err := repository.NewRepo(database)
+	testutil.AssertNoErr(t, err)
+
+	empty := filepath.Join(dir, "empty.sqlite")
+	testutil.AssertNoErr(t, os.WriteFile(empty, nil, 0o644))
+
+	text := filepath.Join(dir, "notes.sqlite")
+	testutil.AssertNoErr(t, os.WriteFile(text, []byte("not a database"), 0o644))
+
+	testCases := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "wh database", path: database, want: true},
+		{name: "empty file", path: empty},
+		{name: "other file", path: text},
+		{name: "missing file", path: filepath.Join(dir, "missing.sqlite")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := repository.IsDatabase(tc.path); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "missing.sqlite")); err == nil {
+		t.Error("Expected no database to be created for a missing file")
+	}
 }
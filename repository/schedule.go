@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robyparr/wh/model"
+)
+
+// CreateSchedule saves the schedule, replacing any schedule that takes effect
+// on the same date.
+func (r *Repo) CreateSchedule(schedule model.Schedule) (model.Schedule, error) {
+	now := time.Now()
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	err := r.withTx(func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec("DELETE FROM schedules WHERE effective_from = ?", schedule.EffectiveFrom); err != nil {
+			return err
+		}
+
+		result, err := tx.NamedExec(`
+			INSERT INTO schedules (
+				effective_from, monday_mins, tuesday_mins, wednesday_mins, thursday_mins,
+				friday_mins, saturday_mins, sunday_mins, created_at, updated_at
+			)
+			VALUES (
+				:effective_from, :monday_mins, :tuesday_mins, :wednesday_mins, :thursday_mins,
+				:friday_mins, :saturday_mins, :sunday_mins, :created_at, :updated_at
+			)
+		`, schedule)
+
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		schedule.Id = int(id)
+		return nil
+	})
+
+	if err != nil {
+		return model.Schedule{}, err
+	}
+
+	return schedule, nil
+}
+
+// GetScheduleForDate returns the schedule in effect on the date, or an empty
+// schedule if none is.
+func (r *Repo) GetScheduleForDate(date time.Time) (model.Schedule, error) {
+	var schedule model.Schedule
+	if err := r.db.Get(&schedule, "SELECT * FROM schedules WHERE effective_from <= ? ORDER BY effective_from DESC LIMIT 1", date); err != nil {
+		if err == sql.ErrNoRows {
+			return model.Schedule{}, nil
+		}
+
+		return model.Schedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func (r *Repo) GetSchedules() ([]model.Schedule, error) {
+	var schedules []model.Schedule
+	if err := r.db.Select(&schedules, "SELECT * FROM schedules ORDER BY effective_from"); err != nil {
+		return []model.Schedule{}, err
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestCreateSchedule(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	schedule := model.NewSchedule(date, 8*time.Hour)
+	got, err := repo.CreateSchedule(schedule)
+	testutil.AssertNoErr(t, err)
+
+	want := schedule
+	want.Id = 1
+	want.CreatedAt = time.Now()
+	want.UpdatedAt = time.Now()
+	testutil.AssertEqualStructs(t, got, want)
+
+	t.Run("replaces schedule on the same date", func(t *testing.T) {
+		replacement := model.NewSchedule(date, 6*time.Hour)
+		_, err := repo.CreateSchedule(replacement)
+		testutil.AssertNoErr(t, err)
+
+		schedules, err := repo.GetSchedules()
+		testutil.AssertNoErr(t, err)
+
+		if len(schedules) != 1 {
+			t.Fatalf("Expected 1 schedule, got %d", len(schedules))
+		}
+
+		if schedules[0].MondayMins != 6*60 {
+			t.Errorf("got %d Monday minutes, want %d", schedules[0].MondayMins, 6*60)
+		}
+	})
+}
+
+func TestGetScheduleForDate(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	first, err := repo.CreateSchedule(model.NewSchedule(time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local), 8*time.Hour))
+	testutil.AssertNoErr(t, err)
+
+	second, err := repo.CreateSchedule(model.NewSchedule(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local), 6*time.Hour))
+	testutil.AssertNoErr(t, err)
+
+	testCases := []struct {
+		name   string
+		date   time.Time
+		wantId int
+	}{
+		{name: "before any schedule", date: time.Date(2022, 12, 31, 0, 0, 0, 0, time.Local), wantId: 0},
+		{name: "first day of first schedule", date: first.EffectiveFrom, wantId: first.Id},
+		{name: "during first schedule", date: time.Date(2023, 8, 31, 0, 0, 0, 0, time.Local), wantId: first.Id},
+		{name: "during second schedule", date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), wantId: second.Id},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := repo.GetScheduleForDate(tc.date)
+			testutil.AssertNoErr(t, err)
+
+			if got.Id != tc.wantId {
+				t.Errorf("got schedule #%d, want #%d", got.Id, tc.wantId)
+			}
+		})
+	}
+}
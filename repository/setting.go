@@ -0,0 +1,12 @@
+package repository
+
+// GetInstallId returns the random ID generated for this database when it was
+// created, which stays the same for as long as the database exists.
+func (r *Repo) GetInstallId() (string, error) {
+	var installId string
+	if err := r.db.Get(&installId, "SELECT value FROM settings WHERE name = 'install_id'"); err != nil {
+		return "", err
+	}
+
+	return installId, nil
+}
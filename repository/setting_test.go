@@ -0,0 +1,30 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestGetInstallId(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	installId, err := repo.GetInstallId()
+	testutil.AssertNoErr(t, err)
+
+	if len(installId) != 32 {
+		t.Errorf("Expected a 32 character install ID, got '%s'", installId)
+	}
+
+	again, err := repo.GetInstallId()
+	testutil.AssertNoErr(t, err)
+	if again != installId {
+		t.Errorf("Expected the install ID to stay '%s', got '%s'", installId, again)
+	}
+
+	other, err := testutil.NewRepo(t).GetInstallId()
+	testutil.AssertNoErr(t, err)
+	if other == installId {
+		t.Errorf("Expected another database to get its own install ID, got '%s' twice", installId)
+	}
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robyparr/wh/model"
+)
+
+// setWorkPeriodTags replaces the work period's tags, creating any tags that
+// don't exist yet.
+func setWorkPeriodTags(tx *sqlx.Tx, period model.WorkPeriod) error {
+	if _, err := tx.Exec("DELETE FROM work_period_tags WHERE work_period_id = ?", period.Id); err != nil {
+		return err
+	}
+
+	for _, tag := range period.Tags {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO tags (name, created_at) VALUES (?, ?)", tag, time.Now()); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`
+			INSERT INTO work_period_tags (work_period_id, tag_id)
+			SELECT ?, id FROM tags WHERE name = ?
+		`, period.Id, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadWorkPeriodTags sets the tags of each work period in place.
+func (r *Repo) loadWorkPeriodTags(periods []model.WorkPeriod) error {
+	if len(periods) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(periods))
+	for i, wp := range periods {
+		ids[i] = wp.Id
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT work_period_tags.work_period_id, tags.name
+		FROM work_period_tags
+		INNER JOIN tags ON tags.id = work_period_tags.tag_id
+		WHERE work_period_tags.work_period_id IN (?)
+		ORDER BY tags.name
+	`, ids)
+	if err != nil {
+		return err
+	}
+
+	var rows []struct {
+		WorkPeriodId int `db:"work_period_id"`
+		Name         string
+	}
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return err
+	}
+
+	tagsByPeriod := make(map[int][]string)
+	for _, row := range rows {
+		tagsByPeriod[row.WorkPeriodId] = append(tagsByPeriod[row.WorkPeriodId], row.Name)
+	}
+
+	for i := range periods {
+		periods[i].Tags = tagsByPeriod[periods[i].Id]
+	}
+
+	return nil
+}
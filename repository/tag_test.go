@@ -0,0 +1,61 @@
+package repository_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/model"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestWorkPeriodTags(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	wd, err := repo.CreateWorkDay(model.NewWorkDay(time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)))
+	testutil.AssertNoErr(t, err)
+
+	wp := model.NewWorkPeriod(wd)
+	wp.AddTags("meeting", "acme")
+	wp, err = repo.CreateWorkPeriod(wp)
+	testutil.AssertNoErr(t, err)
+
+	assertTags := func(t *testing.T, want []string) {
+		t.Helper()
+
+		got, err := repo.GetWorkPeriodById(wp.Id)
+		testutil.AssertNoErr(t, err)
+
+		if !reflect.DeepEqual(got.Tags, want) {
+			t.Errorf("got tags %v, want %v", got.Tags, want)
+		}
+	}
+
+	t.Run("created with tags", func(t *testing.T) {
+		assertTags(t, []string{"acme", "meeting"})
+	})
+
+	t.Run("tags replaced on update", func(t *testing.T) {
+		wp.Tags = nil
+		wp.AddTags("admin")
+		_, err := repo.UpdateWorkPeriod(wp)
+		testutil.AssertNoErr(t, err)
+
+		assertTags(t, []string{"admin"})
+	})
+
+	t.Run("tags loaded with work days", func(t *testing.T) {
+		workDays, err := repo.GetWorkDaysInRange(wd.Date, wd.Date)
+		testutil.AssertNoErr(t, err)
+
+		got := workDays[0].WorkPeriods()[0].Tags
+		if !reflect.DeepEqual(got, []string{"admin"}) {
+			t.Errorf("got tags %v, want %v", got, []string{"admin"})
+		}
+	})
+
+	t.Run("deleting the work period", func(t *testing.T) {
+		testutil.AssertNoErr(t, repo.DeleteWorkPeriod(wp))
+		testutil.AssertNoErr(t, repo.DeleteWorkDay(wd))
+	})
+}
@@ -4,13 +4,19 @@ import (
 	"embed"
 	"io"
 	"text/template"
+
+	"github.com/robyparr/wh/util"
 )
 
 //go:embed *.txt
 var templates embed.FS
 
+var funcs = template.FuncMap{
+	"underline": util.Underline,
+}
+
 func Render(out io.Writer, path string, data any) error {
-	tmpl, err := template.ParseFS(templates, path)
+	tmpl, err := template.New(path).Funcs(funcs).ParseFS(templates, path)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,223 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var clockRegex = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+var relativeRegex = regexp.MustCompile(`^([+-])?(?:(\d+)d)?(?:(\d+)h)?(?:(\d+)m)?$`)
+var meridiemRegex = regexp.MustCompile(`\s+(am|pm)$`)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseTimeString parses a time expression, placing times without a date on
+// today. See ParseTimeStringOn for the accepted expressions.
+func ParseTimeString(str string) (time.Time, error) {
+	return ParseTimeStringOn(str, TodayAtMidnight())
+}
+
+// ParseTimeStringOn parses a time expression, placing times without a date on
+// the given date. It accepts:
+//
+//   - nothing or "now" for the current time
+//   - offsets from now such as "1h30m", "-30m" or "-1d"
+//   - clock times such as "09:15", "9:15", "9am", "9:15pm", "noon" and "midnight"
+//   - a date expression followed by a clock time, e.g. "yesterday 17:00",
+//     "last friday 9am" or "2023-09-14 09:00"
+func ParseTimeStringOn(str string, date time.Time) (time.Time, error) {
+	return parseTimeExpr(str, date, time.Now())
+}
+
+// ParseClockTimeOn parses a clock time such as "09:15", "9am" or "noon" on
+// the given date. Unlike ParseTimeStringOn it rejects "now", offsets from now
+// and date expressions, which would place the time on another day.
+func ParseClockTimeOn(str string, date time.Time) (time.Time, error) {
+	expr := meridiemRegex.ReplaceAllString(strings.ToLower(strings.TrimSpace(str)), "$1")
+	if expr == "" {
+		return time.Time{}, errors.New("missing time, expected a clock time such as 09:15, 9am or noon")
+	}
+
+	clock, ok := parseClock(expr)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid time '%s', expected a clock time such as 09:15, 9am or noon", str)
+	}
+
+	return TimeAtMidnight(date).Add(clock), nil
+}
+
+// ParseDateString parses a date expression: ISO dates, dates in the configured
+// format, "today", "yesterday", "tomorrow", weekdays such as "friday" or
+// "last friday", and day offsets such as "-1d".
+func ParseDateString(str string) (time.Time, error) {
+	date, ok := parseDateExpr(strings.TrimSpace(str), time.Now())
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid date '%s', expected e.g. %s, yesterday, last friday or -1d", str, DateFormatStr)
+	}
+
+	return date, nil
+}
+
+func parseTimeExpr(str string, date time.Time, now time.Time) (time.Time, error) {
+	expr := meridiemRegex.ReplaceAllString(strings.ToLower(strings.TrimSpace(str)), "$1")
+	if expr == "" || expr == "now" {
+		return now, nil
+	}
+
+	if offset, ok := parseRelative(expr); ok {
+		return now.Add(offset), nil
+	}
+
+	if clock, ok := parseClock(expr); ok {
+		return TimeAtMidnight(date).Add(clock), nil
+	}
+
+	// Otherwise it has to be a date expression followed by a clock time.
+	split := strings.LastIndex(expr, " ")
+	if split == -1 {
+		if _, ok := parseDateExpr(expr, now); ok {
+			return time.Time{}, fmt.Errorf("missing time in '%s', expected e.g. '%s 17:00'", str, expr)
+		}
+
+		return time.Time{}, invalidTimeError(str)
+	}
+
+	day, ok := parseDateExpr(expr[:split], now)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid date in '%s', expected e.g. yesterday, last friday or %s", str, DateFormatStr)
+	}
+
+	clock, ok := parseClock(expr[split+1:])
+	if !ok {
+		return time.Time{}, invalidTimeError(str)
+	}
+
+	return day.Add(clock), nil
+}
+
+func invalidTimeError(str string) error {
+	return fmt.Errorf("invalid time '%s', expected e.g. 09:15, 9am, noon, yesterday 17:00 or -1h30m", str)
+}
+
+// parseRelative parses offsets such as "1h30m", "-30m" and "-1d".
+func parseRelative(expr string) (time.Duration, bool) {
+	match := relativeRegex.FindStringSubmatch(expr)
+	if match == nil || match[2]+match[3]+match[4] == "" {
+		return 0, false
+	}
+
+	var offset time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute} {
+		if match[i+2] == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, false
+		}
+
+		offset += time.Duration(n) * unit
+	}
+
+	if match[1] == "-" {
+		offset = -offset
+	}
+
+	return offset, true
+}
+
+// parseClock parses a time of day into the duration since midnight.
+func parseClock(expr string) (time.Duration, bool) {
+	switch expr {
+	case "noon":
+		return 12 * time.Hour, true
+	case "midnight":
+		return 0, true
+	}
+
+	match := clockRegex.FindStringSubmatch(expr)
+	if match == nil {
+		return 0, false
+	}
+
+	// A bare number like "9" is ambiguous, so require minutes or am/pm.
+	hasMinutes, meridiem := match[2] != "", match[3]
+	if !hasMinutes && meridiem == "" {
+		return 0, false
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	var min int
+	if hasMinutes {
+		min, _ = strconv.Atoi(match[2])
+	}
+
+	if meridiem != "" {
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || min > 59 {
+		return 0, false
+	}
+
+	return time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute, true
+}
+
+// parseDateExpr parses a date expression relative to now.
+func parseDateExpr(expr string, now time.Time) (time.Time, bool) {
+	today := TimeAtMidnight(now)
+	lower := strings.ToLower(expr)
+
+	switch lower {
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+
+	if offset, ok := parseRelative(lower); ok && offset%(24*time.Hour) == 0 {
+		return today.AddDate(0, 0, int(offset/(24*time.Hour))), true
+	}
+
+	// "friday" is the most recent Friday including today, while
+	// "last friday" is the one before today.
+	name, last := strings.CutPrefix(lower, "last ")
+	if weekday, ok := weekdays[name]; ok {
+		daysAgo := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if last && daysAgo == 0 {
+			daysAgo = 7
+		}
+
+		return today.AddDate(0, 0, -daysAgo), true
+	}
+
+	for _, layout := range []string{DateFormatStr, settings.DateFormat} {
+		if date, err := time.Parse(layout, expr); err == nil {
+			return TimeAtMidnight(date), true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,155 @@
+package util_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robyparr/wh/util"
+	"github.com/robyparr/wh/util/testutil"
+)
+
+func TestParseTimeString(t *testing.T) {
+	midnight := util.TodayAtMidnight()
+
+	testCases := []struct {
+		name  string
+		input string
+		want  time.Time
+	}{
+		{name: "empty string", input: "", want: time.Now()},
+		{name: "exact time", input: "09:30", want: midnight.Add(9 * time.Hour).Add(30 * time.Minute)},
+		{name: "exact time afternoon", input: "13:00", want: midnight.Add(13 * time.Hour)},
+		{name: "relative time", input: "1h30m", want: time.Now().Add(90 * time.Minute)},
+		{name: "relative time mins", input: "30m", want: time.Now().Add(30 * time.Minute)},
+		{name: "relative time past", input: "-30m", want: time.Now().Add(-30 * time.Minute)},
+		{name: "relative days", input: "-1d", want: time.Now().AddDate(0, 0, -1)},
+		{name: "now", input: "now", want: time.Now()},
+		{name: "single digit hour", input: "9:15", want: midnight.Add(9*time.Hour + 15*time.Minute)},
+		{name: "am", input: "9am", want: midnight.Add(9 * time.Hour)},
+		{name: "pm with minutes", input: "5:30 PM", want: midnight.Add(17*time.Hour + 30*time.Minute)},
+		{name: "12am", input: "12am", want: midnight},
+		{name: "noon", input: "noon", want: midnight.Add(12 * time.Hour)},
+		{name: "midnight", input: "midnight", want: midnight},
+		{name: "yesterday", input: "yesterday 17:00", want: midnight.AddDate(0, 0, -1).Add(17 * time.Hour)},
+		{name: "iso date", input: "2023-09-14 9am", want: time.Date(2023, 9, 14, 9, 0, 0, 0, time.Local)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := util.ParseTimeString(tc.input)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertAroundTime(t, "result", got, tc.want)
+		})
+	}
+}
+
+func TestParseTimeStringOn(t *testing.T) {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name  string
+		input string
+		want  time.Time
+	}{
+		{name: "exact time", input: "09:05", want: date.Add(9*time.Hour + 5*time.Minute)},
+		{name: "relative time", input: "-30m", want: time.Now().Add(-30 * time.Minute)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := util.ParseTimeStringOn(tc.input, date)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertAroundTime(t, "result", got, tc.want)
+		})
+	}
+}
+
+func TestParseClockTimeOn(t *testing.T) {
+	date := time.Date(2023, 9, 1, 0, 0, 0, 0, time.Local)
+
+	got, err := util.ParseClockTimeOn("9:05 pm", date)
+	testutil.AssertNoErr(t, err)
+
+	if want := date.Add(21*time.Hour + 5*time.Minute); !got.Equal(want) {
+		t.Errorf("got '%v', want '%v'", got, want)
+	}
+
+	for _, input := range []string{"", "now", "-30m", "1h", "yesterday 17:00", "2023-09-14 09:00"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := util.ParseClockTimeOn(input, date); err == nil {
+				t.Error("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestParseTimeStringErrors(t *testing.T) {
+	testCases := []struct {
+		input   string
+		wantErr string
+	}{
+		{input: "9", wantErr: "invalid time '9'"},
+		{input: "25:00", wantErr: "invalid time '25:00'"},
+		{input: "13pm", wantErr: "invalid time '13pm'"},
+		{input: "soon", wantErr: "invalid time 'soon'"},
+		{input: "yesterday", wantErr: "missing time in 'yesterday'"},
+		{input: "someday 09:00", wantErr: "invalid date in 'someday 09:00'"},
+		{input: "yesterday later", wantErr: "invalid time 'yesterday later'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := util.ParseTimeString(tc.input)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+				t.Errorf("got error '%v', want prefix '%s'", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDateString(t *testing.T) {
+	today := util.TodayAtMidnight()
+	daysSince := func(weekday time.Weekday) int {
+		return (int(today.Weekday()) - int(weekday) + 7) % 7
+	}
+	last := func(weekday time.Weekday) time.Time {
+		if daysSince(weekday) == 0 {
+			return today.AddDate(0, 0, -7)
+		}
+
+		return today.AddDate(0, 0, -daysSince(weekday))
+	}
+
+	testCases := []struct {
+		input string
+		want  time.Time
+	}{
+		{input: "2023-09-14", want: time.Date(2023, 9, 14, 0, 0, 0, 0, time.Local)},
+		{input: "today", want: today},
+		{input: "Yesterday", want: today.AddDate(0, 0, -1)},
+		{input: "tomorrow", want: today.AddDate(0, 0, 1)},
+		{input: "-1d", want: today.AddDate(0, 0, -1)},
+		{input: "-7d", want: today.AddDate(0, 0, -7)},
+		{input: "friday", want: today.AddDate(0, 0, -daysSince(time.Friday))},
+		{input: "last friday", want: last(time.Friday)},
+		{input: "last mon", want: last(time.Monday)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := util.ParseDateString(tc.input)
+			testutil.AssertNoErr(t, err)
+
+			if !got.Equal(tc.want) {
+				t.Errorf("got '%v', want '%v'", got, tc.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "someday", "2023-13-01", "-1h"} {
+		if _, err := util.ParseDateString(input); err == nil {
+			t.Errorf("ParseDateString(%s): expected an error but got none", input)
+		}
+	}
+}
@@ -84,7 +84,7 @@ func AssertEqualStructs(t *testing.T, got any, want any) {
 
 			isEqual = gotNullTime.Valid == wantNullTime.Valid && isAroundTime(gotNullTime.Time, wantNullTime.Time)
 		default:
-			isEqual = gotValue == wantValue
+			isEqual = reflect.DeepEqual(gotValue, wantValue)
 		}
 
 		if !isEqual {
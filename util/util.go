@@ -2,89 +2,82 @@ package util
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
 
 const DateFormatStr string = "2006-01-02"
 
-var exactTimeRegex = regexp.MustCompile(`^\d{2}:\d{2}$`)
-var relativeTimeRegex = regexp.MustCompile(`^(-?\d+h(\d+m)?)|(-?\d+m)$`)
-
-func TodayAtMidnight() time.Time {
-	return timeAtMidnight(time.Now())
+// Settings control how dates and times are displayed and where weeks start.
+type Settings struct {
+	DateFormat  string
+	Clock24Hour bool
+	WeekStart   time.Weekday
 }
 
-func timeAtMidnight(t time.Time) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
-}
+var settings = DefaultSettings()
 
-func FormatDate(t time.Time) string {
-	return t.Format(DateFormatStr)
+func DefaultSettings() Settings {
+	return Settings{
+		DateFormat: DateFormatStr,
+		WeekStart:  time.Monday,
+	}
 }
 
-func FormatDateTime(t time.Time) string {
-	return t.Format("2006-01-02 3:04 PM")
+func Configure(s Settings) {
+	settings = s
 }
 
-func ParseTimeString(str string) (time.Time, error) {
-	startAt := time.Now()
-
-	switch {
-	case exactTimeRegex.MatchString(str):
-		duration, err := parseExactTimeString(str)
-		if err != nil {
-			return time.Time{}, err
-		}
+func TodayAtMidnight() time.Time {
+	return TimeAtMidnight(time.Now())
+}
 
-		startAt = TodayAtMidnight().Add(duration)
-	case relativeTimeRegex.MatchString(str):
-		duration, err := time.ParseDuration(str)
-		if err != nil {
-			return time.Time{}, err
-		}
+func TimeAtMidnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
 
-		startAt = startAt.Add(duration)
-	}
+func StartOfWeek(t time.Time) time.Time {
+	daysSinceWeekStart := (int(t.Weekday()) - int(settings.WeekStart) + 7) % 7
+	return TimeAtMidnight(t).AddDate(0, 0, -daysSinceWeekStart)
+}
 
-	return startAt, nil
+func StartOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
 }
 
-func parseExactTimeString(str string) (time.Duration, error) {
-	timeStrParts := strings.Split(str, ":")
-	hour, err := strconv.Atoi(timeStrParts[0])
-	if err != nil {
-		return 0, nil
-	}
-	min, err := strconv.Atoi(timeStrParts[1])
-	if err != nil {
-		return 0, err
-	}
+func FormatDate(t time.Time) string {
+	return t.Format(settings.DateFormat)
+}
 
-	totalMinutes := (hour * 60) + min
-	return time.Duration(totalMinutes) * time.Minute, nil
+func FormatDateTime(t time.Time) string {
+	return FormatDate(t) + " " + FormatTime(t)
 }
 
-func ParseDateString(str string) (time.Time, error) {
-	date, err := time.Parse("2006-01-02", str)
-	if err != nil {
-		return time.Time{}, err
+func FormatTime(t time.Time) string {
+	if settings.Clock24Hour {
+		return t.Format("15:04")
 	}
 
-	return timeAtMidnight(date), nil
+	return t.Format("3:04 PM")
 }
 
 func FormatDuration(d time.Duration) string {
+	d = d.Truncate(time.Minute)
 	if d == 0 {
 		return "0m"
 	}
 
-	d = d.Truncate(time.Minute)
 	return strings.Replace(d.String(), "0s", "", 1)
 }
 
+func FormatBalance(d time.Duration) string {
+	if d >= time.Minute {
+		return "+" + FormatDuration(d)
+	}
+
+	return FormatDuration(d)
+}
+
 func Underline(str string) string {
 	underline := strings.Repeat("=", len(str))
 	return fmt.Sprintf("%s\n%s", str, underline)
@@ -29,27 +29,69 @@ func TestFormatDate(t *testing.T) {
 	}
 }
 
-func TestParseTimeString(t *testing.T) {
-	midnight := util.TodayAtMidnight()
+func TestStartOfWeek(t *testing.T) {
+	monday := time.Date(2023, 9, 4, 0, 0, 0, 0, time.Local)
 
 	testCases := []struct {
 		name  string
-		input string
-		want  time.Time
+		input time.Time
 	}{
-		{name: "empty string", input: "", want: time.Now()},
-		{name: "exact time", input: "09:30", want: midnight.Add(9 * time.Hour).Add(30 * time.Minute)},
-		{name: "exact time afternoon", input: "13:00", want: midnight.Add(13 * time.Hour)},
-		{name: "relative time", input: "1h30m", want: time.Now().Add(90 * time.Minute)},
-		{name: "relative time mins", input: "30m", want: time.Now().Add(30 * time.Minute)},
-		{name: "relative time past", input: "-30m", want: time.Now().Add(-30 * time.Minute)},
+		{name: "monday", input: monday.Add(9 * time.Hour)},
+		{name: "wednesday", input: time.Date(2023, 9, 6, 13, 0, 0, 0, time.Local)},
+		{name: "sunday", input: time.Date(2023, 9, 10, 23, 0, 0, 0, time.Local)},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := util.ParseTimeString(tc.input)
-			testutil.AssertNoErr(t, err)
-			testutil.AssertAroundTime(t, "result", got, tc.want)
+			got := util.StartOfWeek(tc.input)
+			if !got.Equal(monday) {
+				t.Errorf("got '%v', want '%v'", got, monday)
+			}
 		})
 	}
 }
+
+func TestFormatBalance(t *testing.T) {
+	testCases := []struct {
+		input time.Duration
+		want  string
+	}{
+		{input: 0, want: "0m"},
+		{input: 30 * time.Second, want: "0m"},
+		{input: 90 * time.Minute, want: "+1h30m"},
+		{input: -45 * time.Minute, want: "-45m"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			got := util.FormatBalance(tc.input)
+			if got != tc.want {
+				t.Errorf("got '%s', want '%s'", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() { util.Configure(util.DefaultSettings()) })
+
+	util.Configure(util.Settings{DateFormat: "02.01.2006", Clock24Hour: true, WeekStart: time.Sunday})
+	date := time.Date(2023, 9, 6, 13, 5, 0, 0, time.Local)
+
+	if got, want := util.FormatDateTime(date), "06.09.2023 13:05"; got != want {
+		t.Errorf("FormatDateTime: got '%s', want '%s'", got, want)
+	}
+
+	if got, want := util.StartOfWeek(date), time.Date(2023, 9, 3, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("StartOfWeek: got '%v', want '%v'", got, want)
+	}
+
+	for _, input := range []string{"2023-09-06", "06.09.2023"} {
+		got, err := util.ParseDateString(input)
+		testutil.AssertNoErr(t, err)
+
+		if want := time.Date(2023, 9, 6, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+			t.Errorf("ParseDateString(%s): got '%v', want '%v'", input, got, want)
+		}
+	}
+}